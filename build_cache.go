@@ -0,0 +1,329 @@
+package nestcsv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// buildCache - the hashes of the last build, to skip marshaling and writing the unchanged tables and code
+//
+//	Only the hashes are kept, so every datasource is read and parsed again, as the enums and refs need every table.
+//	A table is marshaled and written if its rows or any enum or id type of the set is changed, and a codegen runs
+//	if its Code model is changed. The files of an output or a codegen are trusted if their contents match
+//	the manifest of its root directory, so editing or removing one rewrites everything of it. The cache is dropped
+//	if the config, a template, a plugin executable or the nestcsv binary is changed.
+type buildCache struct {
+	path string
+	prev *buildCacheData
+	next *buildCacheData
+
+	mu              sync.Mutex
+	tables          map[string]string // the hash of each table, with the set
+	outputIntact    []bool
+	outputUnchanged []bool // every table of an aggregate output is unchanged
+	codegenIntact   []bool
+}
+
+type buildCacheData struct {
+	Config   string              `json:"config"`
+	Outputs  []*buildCacheTarget `json:"outputs"`
+	Codegens []*buildCacheTarget `json:"codegens"`
+}
+
+// buildCacheTarget - an output or a codegen
+type buildCacheTarget struct {
+	Dir    string            `json:"dir"`              // the manifest hash of the root directory, if its files match it
+	Tables map[string]string `json:"tables,omitempty"` // the table hashes written by an output
+	Code   string            `json:"code,omitempty"`   // the Code model hash of a codegen
+}
+
+func loadBuildCache(config *Config) (*buildCache, error) {
+	configHash, err := buildConfigHash(config)
+	if err != nil {
+		return nil, err
+	}
+	c := &buildCache{
+		path: config.CacheFile,
+		prev: &buildCacheData{},
+		next: &buildCacheData{
+			Config:   configHash,
+			Outputs:  make([]*buildCacheTarget, len(config.Outputs)),
+			Codegens: make([]*buildCacheTarget, len(config.Codegens)),
+		},
+	}
+	for i := range config.Outputs {
+		c.next.Outputs[i] = &buildCacheTarget{Tables: make(map[string]string)}
+	}
+	for i := range config.Codegens {
+		c.next.Codegens[i] = &buildCacheTarget{}
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("failed to read the cache file: %s, %w", c.path, err)
+	}
+	var prev buildCacheData
+	if err := json.Unmarshal(data, &prev); err != nil || prev.Config != configHash {
+		// a broken or outdated cache is rebuilt
+		return c, nil
+	}
+	c.prev = &prev
+	return c, nil
+}
+
+// buildConfigHash - the config after the when conditions are applied, the templates of the codegens,
+// and the binary generating the files
+func buildConfigHash(config *Config) (string, error) {
+	h := sha256.New()
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash the config: %w", err)
+	}
+	h.Write(data)
+	targets := make([]any, 0, len(config.Outputs)+len(config.Codegens))
+	for _, output := range config.Outputs {
+		targets = append(targets, output.loaded)
+	}
+	for _, codegen := range config.Codegens {
+		targets = append(targets, codegen.loaded)
+	}
+	for _, target := range targets {
+		v := reflect.Indirect(reflect.ValueOf(target))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		if field := v.FieldByName("TemplateDir"); field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+			if err := writeDirHash(h, field.String()); err != nil {
+				return "", fmt.Errorf("failed to hash the templates: %s, %w", field.String(), err)
+			}
+		}
+		// the command of a plugin, found in the PATH as the plugin runs it
+		if field := v.FieldByName("Command"); field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+			path, err := exec.LookPath(field.String())
+			if err != nil {
+				return "", fmt.Errorf("failed to find the plugin: %s, %w", field.String(), err)
+			}
+			if err := writeFileHash(h, path); err != nil {
+				return "", fmt.Errorf("failed to hash the plugin: %s, %w", path, err)
+			}
+		}
+	}
+	if exe, err := os.Executable(); err == nil {
+		if info, err := os.Stat(exe); err == nil {
+			fmt.Fprintf(h, "%s %d %d\n", exe, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// prepare - hashes the tables and checks the root directories, before anything is written
+func (c *buildCache) prepare(config *Config, set *TableSet) error {
+	setHash := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(set.Enums)) {
+		fmt.Fprintf(setHash, "enum %s %q\n", name, set.Enums[name].Values)
+	}
+	for _, tableData := range set.Tables {
+		fmt.Fprintf(setHash, "table %s %s\n", tableData.Name, tableData.IDFieldType())
+	}
+	setSum := setHash.Sum(nil)
+
+	c.tables = make(map[string]string, len(set.Tables))
+	for _, tableData := range set.Tables {
+		h := sha256.New()
+		h.Write(setSum)
+		if err := json.NewEncoder(h).Encode([]any{tableData.Name, tableData.rows}); err != nil {
+			return err
+		}
+		c.tables[tableData.Name] = hex.EncodeToString(h.Sum(nil))
+	}
+
+	c.outputIntact = make([]bool, len(config.Outputs))
+	c.outputUnchanged = make([]bool, len(config.Outputs))
+	for i, output := range config.Outputs {
+		prev := c.prevTarget(c.prev.Outputs, i)
+		c.outputIntact[i] = prev != nil && prev.Dir != "" && prev.Dir == c.dirHash(output.loaded)
+		// a removed table rewrites everything, so that its files are pruned
+		if c.outputIntact[i] {
+			for name := range prev.Tables {
+				if _, ok := c.tables[name]; !ok {
+					c.outputIntact[i] = false
+				}
+			}
+		}
+		c.outputUnchanged[i] = c.outputIntact[i] && len(prev.Tables) == len(c.tables)
+		for name, tableHash := range c.tables {
+			if c.outputUnchanged[i] && prev.Tables[name] != tableHash {
+				c.outputUnchanged[i] = false
+			}
+		}
+	}
+	c.codegenIntact = make([]bool, len(config.Codegens))
+	for i, codegen := range config.Codegens {
+		prev := c.prevTarget(c.prev.Codegens, i)
+		c.codegenIntact[i] = prev != nil && prev.Dir != "" && prev.Dir == c.dirHash(codegen.loaded)
+	}
+	return nil
+}
+
+func (c *buildCache) prevTarget(targets []*buildCacheTarget, i int) *buildCacheTarget {
+	if i < len(targets) {
+		return targets[i]
+	}
+	return nil
+}
+
+// skipOutput - whether the table is written already, an aggregate output is skipped only if every table is unchanged
+func (c *buildCache) skipOutput(i int, output *OutputConfig, tableData *TableData) bool {
+	tableHash := c.tables[tableData.Name]
+	c.mu.Lock()
+	c.next.Outputs[i].Tables[tableData.Name] = tableHash
+	c.mu.Unlock()
+
+	if output.IsAggregate() {
+		return c.outputUnchanged[i]
+	}
+	prev := c.prevTarget(c.prev.Outputs, i)
+	return c.outputIntact[i] && prev.Tables[tableData.Name] == tableHash
+}
+
+// skipCodegen - whether the code is generated from the same Code model already
+func (c *buildCache) skipCodegen(i int, code *Code) bool {
+	h := sha256.New()
+	writeCodeHash(h, code)
+	codeHash := hex.EncodeToString(h.Sum(nil))
+	c.next.Codegens[i].Code = codeHash
+
+	prev := c.prevTarget(c.prev.Codegens, i)
+	return c.codegenIntact[i] && prev.Code == codeHash
+}
+
+// save - writes the cache after a successful build, the root directories are listed after everything is written
+func (c *buildCache) save(config *Config) error {
+	for i, output := range config.Outputs {
+		c.next.Outputs[i].Dir = c.dirHash(output.loaded)
+	}
+	for i, codegen := range config.Codegens {
+		c.next.Codegens[i].Dir = c.dirHash(codegen.loaded)
+	}
+
+	data, err := json.Marshal(c.next)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write the cache file: %s, %w", c.path, err)
+	}
+	return nil
+}
+
+// remove - a failed build may have written a part of the files, so the next build starts over
+func (c *buildCache) remove() {
+	_ = os.Remove(c.path)
+}
+
+// dirHash - the hash of the manifest of the root directory of an output or a codegen,
+// empty if it doesn't exist or a file of it is missing or changed
+func (c *buildCache) dirHash(target any) string {
+	manifestPath := filepath.Join(targetRootDir(target), manifestFileName)
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return ""
+	}
+	manifest, err := readManifest(manifestPath)
+	if err != nil || manifest == nil {
+		return ""
+	}
+	for _, file := range manifest.Files {
+		path, ok := manifestFilePath(targetRootDir(target), file.Path)
+		if !ok {
+			return ""
+		}
+		fileData, err := os.ReadFile(path)
+		if err != nil || int64(len(fileData)) != file.Size {
+			return ""
+		}
+		if sum := sha256.Sum256(fileData); hex.EncodeToString(sum[:]) != file.SHA256 {
+			return ""
+		}
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeDirHash - writes the files of the directory and their contents
+func writeDirHash(w io.Writer, dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		return writeFileHash(w, path)
+	})
+}
+
+// writeFileHash - writes the file and its content
+func writeFileHash(w io.Writer, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	fmt.Fprintf(w, "%s %x\n", filepath.ToSlash(path), sum)
+	return nil
+}
+
+// writeCodeHash - writes the Code model, a reference to another file is written by its name
+func writeCodeHash(w io.Writer, code *Code) {
+	writeStruct := func(s *CodeStruct) {
+		fmt.Fprintf(w, "struct %s\n", s.Name)
+		for _, field := range s.Fields {
+			var structRef, enumRef, tableRef string
+			if field.StructRef != nil {
+				structRef = field.StructRef.Name
+			}
+			if field.EnumRef != nil {
+				enumRef = field.EnumRef.Name
+			}
+			if field.TableRef != nil {
+				tableRef = field.TableRef.Name
+			}
+			fmt.Fprintf(w, "field %s %s %t %t %s %s %s\n",
+				field.Name, field.Type, field.IsArray, field.IsNullable, structRef, enumRef, tableRef)
+		}
+	}
+
+	for _, files := range [][]*CodeFile{code.Tables, code.NamedStructs, code.Enums} {
+		for _, file := range files {
+			fmt.Fprintf(w, "file %s %t %t %v %s %d\n", file.Name, file.IsTable, file.IsMap, file.FieldTypes, file.IDFieldType, file.BinSchema)
+			if file.IDField != nil {
+				fmt.Fprintf(w, "id %s\n", file.IDField.Name)
+			}
+			if file.Struct != nil {
+				writeStruct(file.Struct)
+			}
+			for _, s := range file.AnonymousStructs {
+				writeStruct(s)
+			}
+			if file.Enum != nil {
+				fmt.Fprintf(w, "enum %s %q\n", file.Enum.Name, file.Enum.Values)
+			}
+			for _, ref := range file.FileRefs {
+				fmt.Fprintf(w, "ref %s\n", ref.Name)
+			}
+		}
+	}
+}
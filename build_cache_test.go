@@ -0,0 +1,141 @@
+package nestcsv
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteCodeHash(t *testing.T) {
+	hashOf := func(code *Code) string {
+		h := sha256.New()
+		writeCodeHash(h, code)
+		return string(h.Sum(nil))
+	}
+
+	// a table referring to itself must not loop
+	file := &CodeFile{IsTable: true, Name: "items", Struct: &CodeStruct{Name: "items"}}
+	file.Struct.Fields = []*CodeStructField{
+		{Name: "ID", Type: FieldTypeInt},
+		{Name: "Parent", Type: FieldTypeInt, TableRef: file},
+	}
+	code := &Code{Tables: []*CodeFile{file}}
+	before := hashOf(code)
+	if hashOf(code) != before {
+		t.Fatal("unstable code hash")
+	}
+
+	file.Struct.Fields[1].IsNullable = true
+	if hashOf(code) == before {
+		t.Error("the code hash didn't change with the field")
+	}
+}
+
+func TestGenerateCache(t *testing.T) {
+	dir := t.TempDir()
+	writeCSV := func(name, value string) {
+		data := ",\nall,all\nID,Value\nint,string\n,\n1," + value + "\n"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeCSV("items.csv", "sword")
+	writeCSV("shops.csv", "main")
+
+	config := &Config{
+		CacheFile:   filepath.Join(dir, ".nestcsv-cache"),
+		Datasources: []DatasourceConfig{NewDatasourceConfig(&DatasourceCSV{Patterns: []string{filepath.Join(dir, "*.csv")}})},
+		Outputs:     []OutputConfig{NewOutputConfig(&TableWriterJSON{RootDir: filepath.Join(dir, "json")}, "all")},
+	}
+	generate := func() {
+		t.Helper()
+		// the modification times must differ between the runs
+		time.Sleep(10 * time.Millisecond)
+		if err := Generate(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stat := func(name string) (time.Time, string) {
+		t.Helper()
+		path := filepath.Join(dir, "json", name)
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.ModTime(), string(data)
+	}
+
+	generate()
+	itemsTime, _ := stat("items.json")
+	shopsTime, _ := stat("shops.json")
+
+	// only the changed table is rewritten
+	writeCSV("items.csv", "shield")
+	generate()
+	if mtime, data := stat("items.json"); mtime.Equal(itemsTime) || data != `[{"ID":1,"Value":"shield"}]` {
+		t.Errorf("the changed table is not rewritten: %s", data)
+	}
+	if mtime, data := stat("shops.json"); !mtime.Equal(shopsTime) || data != `[{"ID":1,"Value":"main"}]` {
+		t.Errorf("the unchanged table is rewritten: %s", data)
+	}
+
+	// an edit of the same size keeping the modification time is found by the content
+	shopsPath := filepath.Join(dir, "json", "shops.json")
+	shopsTime, _ = stat("shops.json")
+	if err := os.WriteFile(shopsPath, []byte(`[{"ID":1,"Value":"mian"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(shopsPath, shopsTime, shopsTime); err != nil {
+		t.Fatal(err)
+	}
+	generate()
+	if _, data := stat("shops.json"); data != `[{"ID":1,"Value":"main"}]` {
+		t.Errorf("the edited output is not rewritten: %s", data)
+	}
+	shopsTime, _ = stat("shops.json")
+
+	// -rebuild removes the cache, so everything is rewritten
+	if err := os.Remove(config.CacheFile); err != nil {
+		t.Fatal(err)
+	}
+	generate()
+	if mtime, data := stat("shops.json"); mtime.Equal(shopsTime) || data != `[{"ID":1,"Value":"main"}]` {
+		t.Errorf("the table is not rewritten without the cache: %s", data)
+	}
+	if data, err := os.ReadFile(config.CacheFile); err != nil {
+		t.Fatal(err)
+	} else if bytes.Contains(data, []byte("shield")) {
+		t.Errorf("the cache keeps the rows: %s", data)
+	}
+}
+
+func TestBuildConfigHashPlugin(t *testing.T) {
+	command := filepath.Join(t.TempDir(), "plugin")
+	if err := os.WriteFile(command, []byte("#!/bin/sh\necho v1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := &Config{
+		Codegens: []CodegenConfig{NewCodegenConfig(&CodegenPlugin{Plugin: Plugin{Command: command}}, "all")},
+	}
+	before, err := buildConfigHash(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(command, []byte("#!/bin/sh\necho v2\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	after, err := buildConfigHash(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before == after {
+		t.Error("the config hash didn't change with the plugin executable")
+	}
+}
@@ -0,0 +1,141 @@
+package nestcsv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// StaleFile - a generated file which is missing or different on disk, or would be removed as it's not generated anymore
+type StaleFile struct {
+	Path    string
+	Missing bool
+	Removed bool
+	Binary  bool
+	Diff    string // the unified diff from the file on disk to the generated one, empty for a binary file
+}
+
+// Check - runs the whole generation into memory and compares the files with the ones on disk, writing nothing
+//
+//	The cache file is ignored, so every table and code is generated. Only the files in the root directories
+//	of the outputs and codegens are compared, not the debug files of the datasources or the protobuf lock file.
+func Check(config *Config) ([]*StaleFile, error) {
+	files, removed, err := generateInMemory(context.Background(), config, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var rootDirs []string
+	for _, output := range config.Outputs {
+		rootDirs = append(rootDirs, targetRootDir(output.loaded))
+	}
+	for _, codegen := range config.Codegens {
+		rootDirs = append(rootDirs, targetRootDir(codegen.loaded))
+	}
+
+	var staleFile *StaleFile
+	var staleFiles []*StaleFile
+	for _, path := range slices.Sorted(maps.Keys(files.data)) {
+		if !inRootDirs(path, rootDirs) {
+			continue
+		}
+		if staleFile, err = compareFile(path, files.data[path]); err != nil {
+			return nil, err
+		}
+		if staleFile != nil {
+			staleFiles = append(staleFiles, staleFile)
+		}
+	}
+	for _, path := range removed {
+		if staleFile, err = compareFile(path, nil); err != nil {
+			return nil, err
+		}
+		if staleFile != nil {
+			staleFiles = append(staleFiles, staleFile)
+		}
+	}
+	return staleFiles, nil
+}
+
+func inRootDirs(path string, rootDirs []string) bool {
+	return slices.ContainsFunc(rootDirs, func(rootDir string) bool {
+		rel, err := filepath.Rel(rootDir, path)
+		return err == nil && filepath.IsLocal(rel)
+	})
+}
+
+// compareFile - nil if the file on disk is the same, a nil data is a file to be removed
+func compareFile(path string, data []byte) (*StaleFile, error) {
+	prev, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read the file: %s, %w", path, err)
+	}
+	if err == nil && bytes.Equal(prev, data) || err != nil && data == nil {
+		return nil, nil
+	}
+
+	staleFile := &StaleFile{
+		Path:    filepath.ToSlash(path),
+		Missing: err != nil,
+		Removed: data == nil,
+		Binary:  isBinary(prev) || isBinary(data),
+	}
+	if staleFile.Binary {
+		return staleFile, nil
+	}
+	fromFile, toFile := "a/"+staleFile.Path, "b/"+staleFile.Path
+	if staleFile.Missing {
+		fromFile = "/dev/null"
+	}
+	if staleFile.Removed {
+		toFile = "/dev/null"
+	}
+	staleFile.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(prev),
+		B:        diffLines(data),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff the file: %s, %w", path, err)
+	}
+	return staleFile, nil
+}
+
+func diffLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return difflib.SplitLines(string(data))
+}
+
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data)
+}
+
+// WriteTo - writes the diff, or a line telling the binary file differs as git does
+func (f *StaleFile) WriteTo(w io.Writer) (int64, error) {
+	var n int
+	var err error
+	switch {
+	case f.Binary && f.Removed:
+		n, err = fmt.Fprintf(w, "Binary file %s is not generated anymore\n", f.Path)
+	case f.Binary && f.Missing:
+		n, err = fmt.Fprintf(w, "Binary file %s is missing\n", f.Path)
+	case f.Binary:
+		n, err = fmt.Fprintf(w, "Binary files a/%s and b/%s differ\n", f.Path, f.Path)
+	default:
+		n, err = io.WriteString(w, f.Diff)
+	}
+	return int64(n), err
+}
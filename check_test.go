@@ -0,0 +1,94 @@
+package nestcsv
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCompareFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items.json")
+	if err := os.WriteFile(path, []byte("{\n  \"a\": 1\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if staleFile, err := compareFile(path, []byte("{\n  \"a\": 1\n}\n")); err != nil || staleFile != nil {
+		t.Fatalf("compareFile(same) = %v, %v, want nil", staleFile, err)
+	}
+
+	staleFile, err := compareFile(path, []byte("{\n  \"a\": 2\n}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staleFile == nil || staleFile.Missing || staleFile.Binary {
+		t.Fatalf("compareFile(changed) = %+v", staleFile)
+	}
+	if !strings.Contains(staleFile.Diff, "-  \"a\": 1\n+  \"a\": 2\n") {
+		t.Errorf("unexpected diff:\n%s", staleFile.Diff)
+	}
+
+	staleFile, err = compareFile(path+".bin", []byte{0, 1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staleFile == nil || !staleFile.Missing || !staleFile.Binary {
+		t.Errorf("compareFile(missing) = %+v", staleFile)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	writeCSV := func(value string) {
+		data := ",\nall,all\nID,Value\nint,string\n,\n1," + value + "\n"
+		if err := os.WriteFile(filepath.Join(dir, "items.csv"), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeCSV("sword")
+	config := &Config{
+		Datasources: []DatasourceConfig{NewDatasourceConfig(&DatasourceCSV{Patterns: []string{filepath.Join(dir, "*.csv")}})},
+		Outputs:     []OutputConfig{NewOutputConfig(&TableWriterJSON{RootDir: filepath.Join(dir, "json")}, "all")},
+		Codegens: []CodegenConfig{NewCodegenConfig(&CodegenProtobuf{
+			RootDir:  filepath.Join(dir, "proto"),
+			LockFile: filepath.Join(dir, "protobuf.lock.yaml"),
+		}, "all")},
+	}
+	if err := Generate(config); err != nil {
+		t.Fatal(err)
+	}
+
+	staleFiles, err := Check(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(staleFiles) != 0 {
+		t.Fatalf("Check after Generate = %v", staleFiles)
+	}
+
+	// the lock file is out of the root directories, so it's not compared
+	if err := os.Remove(filepath.Join(dir, "protobuf.lock.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	writeCSV("shield")
+	staleFiles, err = Check(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the manifest lists the hash of the stale output
+	var paths []string
+	for _, staleFile := range staleFiles {
+		if staleFile.Missing || staleFile.Removed {
+			t.Errorf("Check of a stale output = %+v", staleFile)
+		}
+		paths = append(paths, staleFile.Path)
+	}
+	jsonDir := filepath.ToSlash(filepath.Join(dir, "json"))
+	if want := []string{jsonDir + "/items.json", jsonDir + "/" + manifestFileName}; !slices.Equal(paths, want) {
+		t.Fatalf("the stale files = %v, want %v", paths, want)
+	}
+	if !strings.Contains(staleFiles[0].Diff, `+[{"ID":1,"Value":"shield"}]`) {
+		t.Errorf("unexpected diff:\n%s", staleFiles[0].Diff)
+	}
+}
@@ -1,32 +1,143 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"github.com/unsafe9/nestcsv"
 	"log"
+	"os"
+	"os/signal"
 	"strings"
 )
 
 func main() {
 	var (
-		configPath  string
-		commandArgs string
+		configPath   string
+		commandArgs  string
+		allErrors    bool
+		reportFormat string
+		rebuild      bool
+		noPrune      bool
 	)
 	flag.StringVar(&configPath, "c", "nestcsv.yaml", "config file path")
 	flag.StringVar(&commandArgs, "a", "", "command arguments")
-	flag.Parse()
+	flag.BoolVar(&allErrors, "all-errors", false, "report every problem instead of stopping at the first one")
+	flag.StringVar(&reportFormat, "report", nestcsv.ReportFormatTable, "error report format with -all-errors: table, json or sarif")
+	flag.BoolVar(&rebuild, "rebuild", false, "ignore the build cache of the cache_file config")
+	flag.BoolVar(&noPrune, "no-prune", false, "keep the files which are not generated anymore")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), "Usage: nestcsv [watch|check] [flags]\n\n"+
+			"  watch\tregenerate whenever a datasource file or the config file changes\n"+
+			"  check\tprint the diff of the stale generated files without writing, and exit 1 if any\n\n")
+		flag.PrintDefaults()
+	}
+
+	// the command can be given before or after the flags
+	osArgs, command := os.Args[1:], ""
+	if len(osArgs) > 0 && !strings.HasPrefix(osArgs[0], "-") {
+		command, osArgs = osArgs[0], osArgs[1:]
+	}
+	_ = flag.CommandLine.Parse(osArgs)
+	if command == "" {
+		command = flag.Arg(0)
+	}
 
 	args := strings.Split(commandArgs, " ")
 	for i := 0; i < len(args); i++ {
 		args[i] = strings.TrimSpace(args[i])
 	}
 
+	switch command {
+	case "", "check":
+	case "watch":
+		watch(configPath, args, allErrors, noPrune, reportFormat)
+		return
+	default:
+		log.Fatalf("unknown command: %s", command)
+	}
+
 	config, err := nestcsv.ParseConfig(configPath, args)
 	if err != nil {
 		log.Fatalf("parse config: %v", err)
 	}
+	if allErrors {
+		config.AllErrors = true
+	}
+	if noPrune {
+		config.NoPrune = true
+	}
+	if command == "check" {
+		check(config, reportFormat)
+		return
+	}
+	if rebuild && config.CacheFile != "" {
+		if err := os.Remove(config.CacheFile); err != nil && !os.IsNotExist(err) {
+			log.Fatalf("remove cache: %v", err)
+		}
+	}
 
 	if err := nestcsv.Generate(config); err != nil {
+		if config.AllErrors {
+			report(err, reportFormat)
+			os.Exit(1)
+		}
 		log.Fatalf("generate: %v", err)
 	}
 }
+
+func watch(configPath string, args []string, allErrors, noPrune bool, reportFormat string) {
+	watcher := &nestcsv.Watcher{
+		ConfigPath: configPath,
+		Args:       args,
+		AllErrors:  allErrors,
+		NoPrune:    noPrune,
+		OnGenerate: func(changed []string, err error) {
+			if len(changed) > 0 {
+				log.Printf("changed: %s", strings.Join(changed, ", "))
+			}
+			switch {
+			case err == nil:
+				log.Printf("generated")
+			case allErrors:
+				report(err, reportFormat)
+			default:
+				log.Printf("generate: %v", err)
+			}
+		},
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	log.Printf("watching the datasources of %s", configPath)
+	if err := watcher.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("watch: %v", err)
+	}
+}
+
+func check(config *nestcsv.Config, reportFormat string) {
+	staleFiles, err := nestcsv.Check(config)
+	if err != nil {
+		if config.AllErrors {
+			report(err, reportFormat)
+			os.Exit(1)
+		}
+		log.Fatalf("check: %v", err)
+	}
+	for _, staleFile := range staleFiles {
+		if _, err := staleFile.WriteTo(os.Stdout); err != nil {
+			log.Fatalf("write diff: %v", err)
+		}
+	}
+	if len(staleFiles) > 0 {
+		log.Printf("%d generated files are stale, run nestcsv to regenerate them", len(staleFiles))
+		os.Exit(1)
+	}
+}
+
+func report(err error, reportFormat string) {
+	if err := nestcsv.CollectTableErrors(err).WriteReport(os.Stdout, reportFormat); err != nil {
+		log.Printf("write report: %v", err)
+	}
+}
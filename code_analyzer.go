@@ -7,10 +7,13 @@ import (
 )
 
 type CodeStructField struct {
-	Name      string
-	Type      FieldType
-	IsArray   bool
-	StructRef *CodeStruct
+	Name       string
+	Type       FieldType
+	IsArray    bool
+	IsNullable bool
+	StructRef  *CodeStruct
+	EnumRef    *CodeEnum
+	TableRef   *CodeFile // the referenced table of a ref field, set only if it's generated with a Find method
 }
 
 type CodeStruct struct {
@@ -18,21 +21,29 @@ type CodeStruct struct {
 	Fields []*CodeStructField
 }
 
+type CodeEnum struct {
+	Name   string
+	Values []string
+}
+
 type CodeFile struct {
 	IsTable          bool
 	IsMap            bool
 	Name             string
 	Struct           *CodeStruct
+	Enum             *CodeEnum
 	AnonymousStructs []*CodeStruct
 	FileRefs         []*CodeFile
 	FieldTypes       []FieldType
 	IDField          *CodeStructField
 	IDFieldType      FieldType // this will be set even if IDField is nil
+	BinSchema        uint32    // the layout hash of the bin output of a table
 }
 
 type Code struct {
 	Tables       []*CodeFile
 	NamedStructs []*CodeFile
+	Enums        []*CodeFile
 }
 
 func (c *Code) Files(yield func(*CodeFile) bool) {
@@ -49,8 +60,11 @@ func (c *Code) Files(yield func(*CodeFile) bool) {
 }
 
 type codeAnalyzer struct {
+	set                   *TableSet
+	refFields             map[*CodeStructField]string
 	namedStructFileFields map[string]*TableField
 	namedStructFiles      map[string]*CodeFile
+	enumFiles             map[string]*CodeFile
 	tableFiles            map[string]*CodeFile
 }
 
@@ -60,6 +74,7 @@ type codeAnalyzerTable struct {
 	fields      []*TableField
 	idField     *TableField
 	idFieldType FieldType
+	binSchema   uint32
 }
 
 func (a *codeAnalyzer) buildStruct(file *CodeFile, table *codeAnalyzerTable, name string, fields []*TableField) (*CodeStruct, error) {
@@ -70,15 +85,33 @@ func (a *codeAnalyzer) buildStruct(file *CodeFile, table *codeAnalyzerTable, nam
 
 	for _, field := range fields {
 		codeField := &CodeStructField{
-			Name:      field.Name,
-			Type:      field.Type,
-			IsArray:   field.IsArray(),
-			StructRef: nil,
+			Name:       field.Name,
+			Type:       field.Type,
+			IsArray:    field.IsArray(),
+			IsNullable: field.IsNullable,
+			StructRef:  nil,
+		}
+		if field.Type == FieldTypeRef {
+			// a ref field holds the id of the referenced table
+			refTable := a.set.Table(field.TypeParam)
+			if refTable == nil {
+				return nil, fmt.Errorf("unknown ref table: %s", field.TypeParam)
+			}
+			codeField.Type = refTable.IDFieldType()
+			a.refFields[codeField] = field.TypeParam
 		}
 		codeStruct.Fields = append(codeStruct.Fields, codeField)
-		file.FieldTypes = appendUnique(file.FieldTypes, field.Type)
+		file.FieldTypes = appendUnique(file.FieldTypes, codeField.Type)
 
-		if field.Type == FieldTypeStruct {
+		if field.Type == FieldTypeEnum {
+			refFile, err := a.getOrAddEnumFile(field.TypeParam)
+			if err != nil {
+				return nil, err
+			}
+			file.FileRefs = appendUnique(file.FileRefs, refFile)
+			codeField.EnumRef = refFile.Enum
+
+		} else if field.Type == FieldTypeStruct {
 			id := field.Identifier()
 			if name, ok := table.metadata.Structs.Get(id); ok {
 				refFile, err := a.getOrAddNamedStructFile(table, name, field)
@@ -149,12 +182,33 @@ func (a *codeAnalyzer) getOrAddNamedStructFile(table *codeAnalyzerTable, name st
 	return file, nil
 }
 
+func (a *codeAnalyzer) getOrAddEnumFile(name string) (*CodeFile, error) {
+	if file, ok := a.enumFiles[name]; ok {
+		return file, nil
+	}
+	enum := a.set.Enum(name)
+	if enum == nil {
+		return nil, fmt.Errorf("unknown enum: %s", name)
+	}
+
+	file := &CodeFile{
+		Name: name,
+		Enum: &CodeEnum{
+			Name:   name,
+			Values: enum.Values,
+		},
+	}
+	a.enumFiles[name] = file
+	return file, nil
+}
+
 func (a *codeAnalyzer) addTableFile(table *codeAnalyzerTable) (*CodeFile, error) {
 	file := &CodeFile{
 		IsTable:     true,
 		IsMap:       table.metadata.AsMap,
 		Name:        table.name,
 		IDFieldType: table.idFieldType,
+		BinSchema:   table.binSchema,
 	}
 	fileStruct, err := a.buildStruct(file, table, table.name, table.fields)
 	if err != nil {
@@ -170,48 +224,76 @@ func (a *codeAnalyzer) addTableFile(table *codeAnalyzerTable) (*CodeFile, error)
 	return file, nil
 }
 
-func AnalyzeTableCode(tableDatas []*TableData, tags []string) (*Code, error) {
-	tables := make([]*codeAnalyzerTable, 0, len(tableDatas))
-	for _, tableData := range tableDatas {
-		fields, err := NewTableParser(tableData).ParseTableFields(tags)
+func newCodeAnalyzer(set *TableSet) *codeAnalyzer {
+	return &codeAnalyzer{
+		set:                   set,
+		refFields:             make(map[*CodeStructField]string),
+		namedStructFileFields: make(map[string]*TableField),
+		namedStructFiles:      make(map[string]*CodeFile),
+		enumFiles:             make(map[string]*CodeFile),
+		tableFiles:            make(map[string]*CodeFile),
+	}
+}
+
+func newCodeAnalyzerTable(schema *TableSchema) *codeAnalyzerTable {
+	var (
+		tableData   = schema.Table
+		fields      = schema.Fields
+		idField     *TableField
+		idFieldType FieldType
+	)
+	if tableData.FieldNames[TableFieldIndexCol] == fields[TableFieldIndexCol].Name {
+		idField = fields[TableFieldIndexCol]
+		idFieldType = idField.Type
+	} else {
+		idFieldType = tableData.IDFieldType()
+	}
+	return &codeAnalyzerTable{
+		name:        tableData.Name,
+		metadata:    tableData.Metadata,
+		fields:      fields,
+		idField:     idField,
+		idFieldType: idFieldType,
+		binSchema:   schema.BinHash(),
+	}
+}
+
+// analyzeTableSchema - builds the code of a single table, for the outputs laid out like the generated code
+func analyzeTableSchema(schema *TableSchema) (*CodeFile, error) {
+	return newCodeAnalyzer(schema.Set).addTableFile(newCodeAnalyzerTable(schema))
+}
+
+func AnalyzeTableCode(set *TableSet, tags []string) (*Code, error) {
+	tables := make([]*codeAnalyzerTable, 0, len(set.Tables))
+	for _, tableData := range set.Tables {
+		if tableData.Metadata.AsEnum {
+			continue
+		}
+		fields, err := NewTableParser(tableData, set).ParseTableFields(tags)
 		if err != nil {
 			return nil, err
 		}
 		if len(fields) == 0 {
 			continue
 		}
-		var (
-			idField     *TableField
-			idFieldType FieldType
-		)
-		if tableData.FieldNames[TableFieldIndexCol] == fields[TableFieldIndexCol].Name {
-			idField = fields[TableFieldIndexCol]
-			idFieldType = idField.Type
-		} else {
-			idFieldType, _ = newFieldType(tableData.FieldTypes[TableFieldIndexCol])
-		}
-		tables = append(tables, &codeAnalyzerTable{
-			name:        tableData.Name,
-			metadata:    tableData.Metadata,
-			fields:      fields,
-			idField:     idField,
-			idFieldType: idFieldType,
-		})
+		tables = append(tables, newCodeAnalyzerTable(&TableSchema{Table: tableData, Fields: fields, Set: set}))
 	}
 
-	a := &codeAnalyzer{
-		namedStructFileFields: make(map[string]*TableField),
-		namedStructFiles:      make(map[string]*CodeFile),
-		tableFiles:            make(map[string]*CodeFile),
-	}
+	a := newCodeAnalyzer(set)
 	for _, table := range tables {
 		if _, err := a.addTableFile(table); err != nil {
 			return nil, err
 		}
 	}
+	for field, refName := range a.refFields {
+		if refFile, ok := a.tableFiles[refName]; ok && (refFile.IDField != nil || refFile.IsMap) {
+			field.TableRef = refFile
+		}
+	}
 	code := &Code{
 		Tables:       make([]*CodeFile, 0, len(a.tableFiles)),
 		NamedStructs: make([]*CodeFile, 0, len(a.namedStructFiles)),
+		Enums:        make([]*CodeFile, 0, len(a.enumFiles)),
 	}
 	for _, file := range a.tableFiles {
 		code.Tables = append(code.Tables, file)
@@ -225,5 +307,11 @@ func AnalyzeTableCode(tableDatas []*TableData, tags []string) (*Code, error) {
 	sort.Slice(code.NamedStructs, func(i, j int) bool {
 		return code.NamedStructs[i].Name < code.NamedStructs[j].Name
 	})
+	for _, file := range a.enumFiles {
+		code.Enums = append(code.Enums, file)
+	}
+	sort.Slice(code.Enums, func(i, j int) bool {
+		return code.Enums[i].Name < code.Enums[j].Name
+	})
 	return code, nil
 }
@@ -1,11 +1,14 @@
 package nestcsv
 
 import (
+	"context"
+
 	"gopkg.in/yaml.v3"
 )
 
+// Codegen - generates the code, creating its files through files
 type Codegen interface {
-	Generate(code *Code) error
+	Generate(ctx context.Context, files *FileSink, code *Code) error
 }
 
 type CodegenConfig struct {
@@ -13,17 +16,28 @@ type CodegenConfig struct {
 	Tags []string `yaml:"tags"`
 
 	exclusiveConfigGroup[Codegen]
-	Go    *CodegenGo    `yaml:"go,omitempty"`
-	UE5   *CodegenUE5   `yaml:"ue5,omitempty"`
-	Unity *CodegenUnity `yaml:"unity,omitempty"`
+	Go         *CodegenGo         `yaml:"go,omitempty"`
+	UE5        *CodegenUE5        `yaml:"ue5,omitempty"`
+	Unity      *CodegenUnity      `yaml:"unity,omitempty"`
+	Protobuf   *CodegenProtobuf   `yaml:"protobuf,omitempty"`
+	TypeScript *CodegenTypeScript `yaml:"typescript,omitempty"`
+	Rust       *CodegenRust       `yaml:"rust,omitempty"`
+	SQLDDL     *CodegenSQLDDL     `yaml:"sql_ddl,omitempty"`
+	Template   *CodegenTemplate   `yaml:"template,omitempty"`
+	Plugin     *CodegenPlugin     `yaml:"plugin,omitempty"`
+}
+
+// NewCodegenConfig - a codegen of the config built in code, such as a custom one
+func NewCodegenConfig(g Codegen, tags ...string) CodegenConfig {
+	return CodegenConfig{Tags: tags, exclusiveConfigGroup: exclusiveConfigGroup[Codegen]{loaded: g}}
 }
 
-func (c *CodegenConfig) Generate(tableDatas []*TableData) error {
-	code, err := AnalyzeTableCode(tableDatas, c.Tags)
+func (c *CodegenConfig) Generate(ctx context.Context, files *FileSink, set *TableSet) error {
+	code, err := AnalyzeTableCode(set, c.Tags)
 	if err != nil {
 		return err
 	}
-	return c.loaded.Generate(code)
+	return c.loaded.Generate(ctx, files, code)
 }
 
 func (c *CodegenConfig) UnmarshalYAML(node *yaml.Node) error {
@@ -2,6 +2,7 @@ package nestcsv
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"go/format"
 	"log"
@@ -18,9 +19,11 @@ type CodegenGo struct {
 	Context     bool   `yaml:"context"`
 	Int32ToInt  bool   `yaml:"int32_to_int"`
 	FileSuffix  string `yaml:"file_suffix"`
+	// TemplateDir - overrides the embedded templates by the file name, e.g. "file.go.tpl"
+	TemplateDir string `yaml:"template_dir"`
 }
 
-func (c *CodegenGo) Generate(code *Code) error {
+func (c *CodegenGo) Generate(ctx context.Context, files *FileSink, code *Code) error {
 	if c.PackageName == "" {
 		c.PackageName = filepath.Base(c.RootDir)
 	}
@@ -32,7 +35,16 @@ func (c *CodegenGo) Generate(code *Code) error {
 		values := map[string]any{
 			"File": file,
 		}
-		if err := c.template(file.Name, "file.go.tpl", values); err != nil {
+		if err := c.template(files, file.Name, "file.go.tpl", values); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range code.Enums {
+		values := map[string]any{
+			"File": file,
+		}
+		if err := c.template(files, file.Name, "enum.go.tpl", values); err != nil {
 			return err
 		}
 	}
@@ -40,21 +52,23 @@ func (c *CodegenGo) Generate(code *Code) error {
 	values := map[string]any{
 		"Tables": code.Tables,
 	}
-	return c.template("nestcsv", "nestcsv.go.tpl", values)
+	return c.template(files, "nestcsv", "nestcsv.go.tpl", values)
 }
 
 var goEmptyImportRegexp = regexp.MustCompile(`import \(\s*\n\s*\)`)
 
-func (c *CodegenGo) template(fileName, templateName string, values map[string]any) error {
-	tmpl, err := template.
-		New(filepath.Base(templateName)).
-		Funcs(templateFuncMap).
-		Funcs(template.FuncMap{
-			"fieldType":          c.fieldType,
-			"fieldElemType":      c.fieldElemType,
-			"fieldPrimitiveType": c.fieldPrimitiveType,
-		}).
-		ParseFS(templateFS, "templates/go/"+templateName)
+func (c *CodegenGo) template(files *FileSink, fileName, templateName string, values map[string]any) error {
+	tmpl, err := parseTemplate(
+		template.
+			New(filepath.Base(templateName)).
+			Funcs(templateFuncMap).
+			Funcs(template.FuncMap{
+				"fieldType":          c.fieldType,
+				"fieldElemType":      c.fieldElemType,
+				"fieldPrimitiveType": c.fieldPrimitiveType,
+			}),
+		c.TemplateDir, "go", templateName,
+	)
 	if err != nil {
 		return fmt.Errorf("error parsing template: %s, %w", templateName, err)
 	}
@@ -83,7 +97,7 @@ func (c *CodegenGo) template(fileName, templateName string, values map[string]an
 		return fmt.Errorf("error formatting source: %s, %w", fileName, err)
 	}
 
-	file, err := createFile(c.RootDir, strings.ToLower(fileName), c.FileSuffix)
+	file, err := files.Create(c.RootDir, strings.ToLower(fileName), c.FileSuffix)
 	if err != nil {
 		return err
 	}
@@ -99,6 +113,9 @@ func (c *CodegenGo) fieldType(f *CodeStructField) string {
 	if f.IsArray {
 		return "[]" + c.fieldElemType(f)
 	}
+	if f.IsNullable && f.Type != FieldTypeJSON {
+		return "*" + c.fieldElemType(f)
+	}
 	return c.fieldElemType(f)
 }
 
@@ -106,6 +123,9 @@ func (c *CodegenGo) fieldElemType(f *CodeStructField) string {
 	if f.Type == FieldTypeStruct {
 		return pascal(f.StructRef.Name)
 	}
+	if f.Type == FieldTypeEnum {
+		return pascal(f.EnumRef.Name)
+	}
 	return c.fieldPrimitiveType(f.Type)
 }
 
@@ -0,0 +1,46 @@
+package nestcsv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCodegenGoFieldType(t *testing.T) {
+	rarity := &CodeEnum{Name: "rarity"}
+	tests := []struct {
+		field *CodeStructField
+		want  string
+	}{
+		{field: &CodeStructField{Type: FieldTypeInt}, want: "int32"},
+		{field: &CodeStructField{Type: FieldTypeInt, IsNullable: true}, want: "*int32"},
+		{field: &CodeStructField{Type: FieldTypeString, IsNullable: true}, want: "*string"},
+		{field: &CodeStructField{Type: FieldTypeTime, IsNullable: true}, want: "*time.Time"},
+		{field: &CodeStructField{Type: FieldTypeEnum, EnumRef: rarity, IsNullable: true}, want: "*Rarity"},
+		{field: &CodeStructField{Type: FieldTypeJSON, IsNullable: true}, want: "interface{}"},
+		{field: &CodeStructField{Type: FieldTypeInt, IsArray: true}, want: "[]int32"},
+	}
+	for _, tt := range tests {
+		if got := (&CodegenGo{}).fieldType(tt.field); got != tt.want {
+			t.Errorf("fieldType(%+v) = %s, want %s", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestCodegenGoTemplateDir(t *testing.T) {
+	templateDir := t.TempDir()
+	override := "package {{ $.PackageName }}\n\n// {{ pascal .File.Name }} - overridden\ntype {{ pascal .File.Name }} int\n"
+	if err := os.WriteFile(filepath.Join(templateDir, "enum.go.tpl"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files := generateCodeFiles(t, &CodegenGo{RootDir: t.TempDir(), PackageName: "table", TemplateDir: templateDir})
+
+	if want := "package table\n\n// Grades - overridden\ntype Grades int\n"; files["grades.go"] != want {
+		t.Errorf("grades.go = %q, want %q", files["grades.go"], want)
+	}
+	// the templates missing in the directory are the embedded ones
+	if !strings.Contains(files["items.go"], "type Items struct {") {
+		t.Errorf("items.go is not generated by the embedded template:\n%s", files["items.go"])
+	}
+}
@@ -0,0 +1,128 @@
+package nestcsv
+
+import "context"
+
+// CodegenPlugin - writes the files a plugin generates from the Code model
+//
+//	The request is {"kind": "codegen", "options": {...}, "code": {...}}, where the code has the "tables",
+//	"named_structs" and "enums" files, and a struct, an enum or a file is referred by its name. The response
+//	is the files as the output plugin's.
+type CodegenPlugin struct {
+	RootDir string `yaml:"root_dir"`
+	Plugin  `yaml:",inline"`
+}
+
+func (c *CodegenPlugin) Generate(ctx context.Context, files *FileSink, code *Code) error {
+	if c.RootDir == "" {
+		c.RootDir = "."
+	}
+	var response pluginFilesResponse
+	if err := c.call(ctx, "codegen", map[string]any{"code": newPluginCode(code)}, &response); err != nil {
+		return err
+	}
+	return writePluginFiles(files, c.RootDir, response.Files)
+}
+
+type pluginCode struct {
+	Tables       []*pluginCodeFile `json:"tables"`
+	NamedStructs []*pluginCodeFile `json:"named_structs"`
+	Enums        []*pluginCodeFile `json:"enums"`
+}
+
+type pluginCodeFile struct {
+	Name             string              `json:"name"`
+	IsTable          bool                `json:"is_table"`
+	IsMap            bool                `json:"is_map"`
+	Struct           *pluginCodeStruct   `json:"struct,omitempty"`
+	Enum             *pluginCodeEnum     `json:"enum,omitempty"`
+	AnonymousStructs []*pluginCodeStruct `json:"anonymous_structs,omitempty"`
+	FileRefs         []string            `json:"file_refs,omitempty"`
+	FieldTypes       []FieldType         `json:"field_types,omitempty"`
+	IDField          string              `json:"id_field,omitempty"`
+	IDFieldType      FieldType           `json:"id_field_type,omitempty"`
+}
+
+type pluginCodeStruct struct {
+	Name   string                   `json:"name"`
+	Fields []*pluginCodeStructField `json:"fields"`
+}
+
+type pluginCodeStructField struct {
+	Name       string    `json:"name"`
+	Type       FieldType `json:"type"`
+	IsArray    bool      `json:"is_array"`
+	IsNullable bool      `json:"is_nullable"`
+	StructRef  string    `json:"struct_ref,omitempty"`
+	EnumRef    string    `json:"enum_ref,omitempty"`
+	TableRef   string    `json:"table_ref,omitempty"`
+}
+
+type pluginCodeEnum struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// newPluginCode - the files refer to each other, so a reference is replaced with the name to be encoded
+func newPluginCode(code *Code) *pluginCode {
+	newFiles := func(files []*CodeFile) []*pluginCodeFile {
+		ret := make([]*pluginCodeFile, len(files))
+		for i, file := range files {
+			ret[i] = newPluginCodeFile(file)
+		}
+		return ret
+	}
+	return &pluginCode{
+		Tables:       newFiles(code.Tables),
+		NamedStructs: newFiles(code.NamedStructs),
+		Enums:        newFiles(code.Enums),
+	}
+}
+
+func newPluginCodeFile(file *CodeFile) *pluginCodeFile {
+	ret := &pluginCodeFile{
+		Name:        file.Name,
+		IsTable:     file.IsTable,
+		IsMap:       file.IsMap,
+		Struct:      newPluginCodeStruct(file.Struct),
+		FieldTypes:  file.FieldTypes,
+		IDFieldType: file.IDFieldType,
+	}
+	if file.Enum != nil {
+		ret.Enum = &pluginCodeEnum{Name: file.Enum.Name, Values: file.Enum.Values}
+	}
+	for _, s := range file.AnonymousStructs {
+		ret.AnonymousStructs = append(ret.AnonymousStructs, newPluginCodeStruct(s))
+	}
+	for _, ref := range file.FileRefs {
+		ret.FileRefs = append(ret.FileRefs, ref.Name)
+	}
+	if file.IDField != nil {
+		ret.IDField = file.IDField.Name
+	}
+	return ret
+}
+
+func newPluginCodeStruct(s *CodeStruct) *pluginCodeStruct {
+	if s == nil {
+		return nil
+	}
+	ret := &pluginCodeStruct{Name: s.Name, Fields: make([]*pluginCodeStructField, len(s.Fields))}
+	for i, field := range s.Fields {
+		ret.Fields[i] = &pluginCodeStructField{
+			Name:       field.Name,
+			Type:       field.Type,
+			IsArray:    field.IsArray,
+			IsNullable: field.IsNullable,
+		}
+		if field.StructRef != nil {
+			ret.Fields[i].StructRef = field.StructRef.Name
+		}
+		if field.EnumRef != nil {
+			ret.Fields[i].EnumRef = field.EnumRef.Name
+		}
+		if field.TableRef != nil {
+			ret.Fields[i].TableRef = field.TableRef.Name
+		}
+	}
+	return ret
+}
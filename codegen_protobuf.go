@@ -0,0 +1,150 @@
+package nestcsv
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+type CodegenProtobuf struct {
+	RootDir string `yaml:"root_dir"`
+	Package string `yaml:"package"`
+	// Options - the string file options, e.g. go_package or csharp_namespace
+	Options map[string]string `yaml:"options"`
+	// LockFile - keeps the field numbers, shared with the protobuf output (default "protobuf.lock.yaml")
+	LockFile string `yaml:"lock_file"`
+}
+
+type protobufEnumValue struct {
+	Value  string // empty for the zero value of an enum whose first value is removed
+	Number int
+}
+
+func (c *CodegenProtobuf) Generate(ctx context.Context, files *FileSink, code *Code) error {
+	if c.RootDir == "" {
+		c.RootDir = "."
+	}
+	if c.LockFile == "" {
+		c.LockFile = defaultProtobufLockFile
+	}
+
+	return withProtobufLock(files, c.LockFile, func(lock *protobufLock) error {
+		for file := range code.Files {
+			lock.assignFile(file)
+		}
+		for _, file := range code.Enums {
+			lock.assignFile(file)
+		}
+
+		for file := range code.Files {
+			if err := c.template(files, lock, file); err != nil {
+				return err
+			}
+		}
+		for _, file := range code.Enums {
+			if err := c.template(files, lock, file); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c *CodegenProtobuf) template(files *FileSink, lock *protobufLock, file *CodeFile) error {
+	tmpl, err := template.
+		New("file.proto.tpl").
+		Funcs(templateFuncMap).
+		Funcs(template.FuncMap{
+			"typeName":           protobufTypeName,
+			"fieldType":          c.fieldType,
+			"fieldPrimitiveType": c.fieldPrimitiveType,
+			"fieldNumber":        lock.fieldNumber,
+			"reservedFields":     lock.reservedFields,
+			"reservedEnumValues": lock.reservedEnumValues,
+			"enumValues": func(e *CodeEnum) []protobufEnumValue {
+				values := make([]protobufEnumValue, 0, len(e.Values)+1)
+				for _, value := range e.Values {
+					values = append(values, protobufEnumValue{Value: value, Number: lock.enumNumber(e, value)})
+				}
+				sort.Slice(values, func(i, j int) bool {
+					return values[i].Number < values[j].Number
+				})
+				if values[0].Number != 0 {
+					values = append([]protobufEnumValue{{Number: 0}}, values...)
+				}
+				return values
+			},
+		}).
+		ParseFS(templateFS, "templates/protobuf/file.proto.tpl")
+	if err != nil {
+		return fmt.Errorf("error parsing template: %s, %w", file.Name, err)
+	}
+
+	out, err := files.Create(c.RootDir, strings.ToLower(file.Name), "proto")
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	options := make([]string, 0, len(c.Options))
+	for key := range c.Options {
+		options = append(options, key)
+	}
+	sort.Strings(options)
+
+	err = tmpl.Execute(out, map[string]any{
+		"File":       file,
+		"Package":    c.Package,
+		"Options":    c.Options,
+		"OptionKeys": options,
+	})
+	if err != nil {
+		return fmt.Errorf("error executing template: %s, %w", filepath.Base(out.Name()), err)
+	}
+	return nil
+}
+
+func (c *CodegenProtobuf) fieldType(f *CodeStructField) string {
+	if f.IsArray {
+		return "repeated " + c.fieldElemType(f)
+	}
+	if f.IsNullable && !in(f.Type, FieldTypeStruct, FieldTypeTime, FieldTypeJSON) {
+		// the messages have the presence already
+		return "optional " + c.fieldElemType(f)
+	}
+	return c.fieldElemType(f)
+}
+
+func (c *CodegenProtobuf) fieldElemType(f *CodeStructField) string {
+	if f.Type == FieldTypeStruct {
+		return protobufTypeName(f.StructRef.Name)
+	}
+	if f.Type == FieldTypeEnum {
+		return protobufTypeName(f.EnumRef.Name)
+	}
+	return c.fieldPrimitiveType(f.Type)
+}
+
+func (c *CodegenProtobuf) fieldPrimitiveType(typ FieldType) string {
+	switch typ {
+	case FieldTypeInt:
+		return "int32"
+	case FieldTypeLong:
+		return "int64"
+	case FieldTypeFloat:
+		return "double"
+	case FieldTypeBool:
+		return "bool"
+	case FieldTypeString:
+		return "string"
+	case FieldTypeTime:
+		return "google.protobuf.Timestamp"
+	case FieldTypeJSON:
+		return "google.protobuf.Value"
+	default:
+		panic("unknown type: " + typ)
+	}
+}
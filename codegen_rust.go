@@ -0,0 +1,143 @@
+package nestcsv
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// CodegenRust - generates a module of serde structs, the crate depends on serde (derive), serde_json and chrono (serde)
+type CodegenRust struct {
+	RootDir string `yaml:"root_dir"`
+}
+
+func (c *CodegenRust) Generate(ctx context.Context, files *FileSink, code *Code) error {
+	for file := range code.Files {
+		values := map[string]any{
+			"File": file,
+		}
+		if err := c.template(files, file.Name, "file.rs.tpl", values); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range code.Enums {
+		values := map[string]any{
+			"File": file,
+		}
+		if err := c.template(files, file.Name, "enum.rs.tpl", values); err != nil {
+			return err
+		}
+	}
+
+	values := map[string]any{
+		"Tables":       code.Tables,
+		"NamedStructs": code.NamedStructs,
+		"Enums":        code.Enums,
+	}
+	return c.template(files, "mod", "mod.rs.tpl", values)
+}
+
+func (c *CodegenRust) template(files *FileSink, fileName, templateName string, values map[string]any) error {
+	tmpl, err := template.
+		New(filepath.Base(templateName)).
+		Funcs(templateFuncMap).
+		Funcs(template.FuncMap{
+			"fieldType":          c.fieldType,
+			"fieldElemType":      c.fieldElemType,
+			"fieldPrimitiveType": c.fieldPrimitiveType,
+			"ident":              rustIdent,
+			"module":             rustModule,
+		}).
+		ParseFS(templateFS, "templates/rust/"+templateName)
+	if err != nil {
+		return fmt.Errorf("error parsing template: %s, %w", templateName, err)
+	}
+
+	file, err := files.Create(c.RootDir, strings.TrimPrefix(rustModule(fileName), "r#"), "rs")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := tmpl.Execute(file, values); err != nil {
+		return fmt.Errorf("error executing template: %s, %w", fileName, err)
+	}
+	return nil
+}
+
+func (c *CodegenRust) fieldType(f *CodeStructField) string {
+	if f.IsArray {
+		return "Vec<" + c.fieldElemType(f) + ">"
+	}
+	if f.IsNullable && f.Type != FieldTypeJSON {
+		return "Option<" + c.fieldElemType(f) + ">"
+	}
+	return c.fieldElemType(f)
+}
+
+func (c *CodegenRust) fieldElemType(f *CodeStructField) string {
+	if f.Type == FieldTypeStruct {
+		return pascal(f.StructRef.Name)
+	}
+	if f.Type == FieldTypeEnum {
+		return pascal(f.EnumRef.Name)
+	}
+	return c.fieldPrimitiveType(f.Type)
+}
+
+func (c *CodegenRust) fieldPrimitiveType(typ FieldType) string {
+	switch typ {
+	case FieldTypeInt:
+		return "i32"
+	case FieldTypeLong:
+		return "i64"
+	case FieldTypeFloat:
+		return "f64"
+	case FieldTypeBool:
+		return "bool"
+	case FieldTypeString:
+		return "String"
+	case FieldTypeTime:
+		return "DateTime<Utc>"
+	case FieldTypeJSON:
+		return "serde_json::Value"
+	default:
+		panic("unknown type: " + typ)
+	}
+}
+
+var (
+	rustWordRegexp = regexp.MustCompile(`[A-Z]+[a-z0-9]*|[a-z0-9]+`)
+	rustKeywords   = []string{
+		"as", "async", "await", "break", "const", "continue", "crate", "dyn", "else", "enum", "extern", "false",
+		"fn", "for", "if", "impl", "in", "let", "loop", "match", "mod", "move", "mut", "pub", "ref", "return",
+		"self", "static", "struct", "super", "trait", "true", "type", "unsafe", "use", "where", "while",
+		"abstract", "become", "box", "do", "final", "gen", "macro", "override", "priv", "try", "typeof",
+		"unsized", "virtual", "yield",
+	}
+)
+
+// rustIdent - the snake_case identifier of a field, a keyword is escaped as a raw identifier
+func rustIdent(name string) string {
+	words := rustWordRegexp.FindAllString(name, -1)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	ident := strings.Join(words, "_")
+	if ident == "" || (ident[0] >= '0' && ident[0] <= '9') {
+		ident = "_" + ident
+	}
+	if has(rustKeywords, ident) {
+		return "r#" + ident
+	}
+	return ident
+}
+
+// rustModule - the module name of a file, which is also the file name
+func rustModule(name string) string {
+	return rustIdent(strings.ToLower(name))
+}
@@ -0,0 +1,90 @@
+package nestcsv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCodegenRust(t *testing.T) {
+	files := generateCodeFiles(t, &CodegenRust{RootDir: t.TempDir()})
+	want := map[string][]string{
+		"items.rs": {
+			"use super::grades::Grades;",
+			"    pub id: i32,",
+			"    pub grade: Option<Grades>,",
+			"    pub shop: String,", // the id type of the referenced table
+			"    pub tags: Vec<String>,",
+			"    pub reward: ItemsReward,",
+			"    pub open_at: Option<DateTime<Utc>>,",
+			"    pub count: Option<i64>,",
+			"    pub note: serde_json::Value,", // a nullable json is not wrapped
+			"    pub fn shop_ref<'a>(&self, tables: &'a TableHolder) -> Option<&'a super::shops::Shops> {",
+			"    pub rows: Vec<Items>,",
+			"    pub fn find(&self, id: i32) -> Option<&Items> {",
+		},
+		"shops.rs": {
+			"    pub rows: HashMap<String, Shops>,",
+			"    pub fn find(&self, id: &str) -> Option<&Shops> {",
+		},
+		"grades.rs": {
+			"pub enum Grades {",
+			"    pub const VALUES: [Grades; 2] = [Grades::Common, Grades::Rare];",
+		},
+		"mod.rs": {
+			"pub mod items;",
+			"    pub shops: ShopsTable,",
+		},
+	}
+	if len(files) != len(want) {
+		t.Errorf("files = %d, want %d", len(files), len(want))
+	}
+	for name, lines := range want {
+		data, ok := files[name]
+		if !ok {
+			t.Errorf("%s is not generated", name)
+			continue
+		}
+		for _, line := range lines {
+			if !strings.Contains(data, line+"\n") {
+				t.Errorf("%s doesn't have %q:\n%s", name, line, data)
+			}
+		}
+	}
+}
+
+func TestCodegenRustFieldType(t *testing.T) {
+	grades := &CodeEnum{Name: "grades"}
+	reward := &CodeStruct{Name: "items_reward"}
+	tests := []struct {
+		field *CodeStructField
+		want  string
+	}{
+		{field: &CodeStructField{Type: FieldTypeInt}, want: "i32"},
+		{field: &CodeStructField{Type: FieldTypeFloat, IsNullable: true}, want: "Option<f64>"},
+		{field: &CodeStructField{Type: FieldTypeEnum, EnumRef: grades}, want: "Grades"},
+		{field: &CodeStructField{Type: FieldTypeEnum, EnumRef: grades, IsArray: true}, want: "Vec<Grades>"},
+		{field: &CodeStructField{Type: FieldTypeStruct, StructRef: reward, IsArray: true}, want: "Vec<ItemsReward>"},
+		{field: &CodeStructField{Type: FieldTypeJSON, IsNullable: true}, want: "serde_json::Value"},
+		{field: &CodeStructField{Type: FieldTypeTime}, want: "DateTime<Utc>"},
+	}
+	for _, tt := range tests {
+		if got := (&CodegenRust{}).fieldType(tt.field); got != tt.want {
+			t.Errorf("fieldType(%+v) = %s, want %s", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestRustIdent(t *testing.T) {
+	tests := map[string]string{
+		"ID":       "id",
+		"OpenAt":   "open_at",
+		"type":     "r#type",
+		"Type":     "r#type",
+		"2ndPrize": "_2nd_prize",
+	}
+	for name, want := range tests {
+		if got := rustIdent(name); got != want {
+			t.Errorf("rustIdent(%s) = %s, want %s", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package nestcsv
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"text/template"
+)
+
+type CodegenSQLDDL struct {
+	RootDir  string `yaml:"root_dir"`
+	Dialect  string `yaml:"dialect"`   // postgres (default), mysql or sqlite
+	FileName string `yaml:"file_name"` // default "schema.sql"
+	// DropTables - drops the existing tables before creating them
+	DropTables bool `yaml:"drop_tables"`
+}
+
+func (c *CodegenSQLDDL) Generate(ctx context.Context, files *FileSink, code *Code) error {
+	if c.FileName == "" {
+		c.FileName = "schema.sql"
+	}
+	dialect, err := newSQLDialect(c.Dialect)
+	if err != nil {
+		return err
+	}
+
+	tables := make([]*sqlTable, 0, len(code.Tables))
+	for _, file := range code.Tables {
+		tables = append(tables, newSQLTables(file)...)
+	}
+
+	file, err := files.Create(c.RootDir, c.FileName, cmp.Or(filepath.Ext(c.FileName), ".sql"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := writeSQLDDL(file, dialect, tables, c.DropTables); err != nil {
+		return fmt.Errorf("error executing template: %s, %w", c.FileName, err)
+	}
+	return nil
+}
+
+// writeSQLDDL - writes the statements creating the tables, dropping them first if dropTables is set
+func writeSQLDDL(w io.Writer, dialect sqlDialect, tables []*sqlTable, dropTables bool) error {
+	drops := slices.Clone(tables)
+	slices.Reverse(drops)
+
+	tmpl, err := template.
+		New("ddl.sql.tpl").
+		Funcs(templateFuncMap).
+		Funcs(template.FuncMap{
+			"quote":      dialect.quote,
+			"quoteList":  dialect.quoteList,
+			"columnType": dialect.columnType,
+			"sqlString":  sqlString,
+		}).
+		ParseFS(templateFS, "templates/sql/ddl.sql.tpl")
+	if err != nil {
+		return fmt.Errorf("error parsing template: %s, %w", "ddl.sql.tpl", err)
+	}
+	return tmpl.Execute(w, map[string]any{
+		"Tables":     tables,
+		"DropTables": dropTables,
+		"Drops":      drops,
+	})
+}
@@ -0,0 +1,151 @@
+package nestcsv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// CodegenTemplate - renders the user templates over the Code model, for a language nestcsv doesn't support
+type CodegenTemplate struct {
+	RootDir     string                `yaml:"root_dir"`
+	TemplateDir string                `yaml:"template_dir"`
+	Files       []CodegenTemplateFile `yaml:"files"`
+	// Types - the type of each field type, "int", "long", "float", "bool", "string", "time" and "json",
+	// and the formats of the "array", "nullable", "struct" and "enum" types, e.g. "List<%s>".
+	// A struct or an enum is formatted with its pascal case name, and the other formats default to "%s"
+	Types map[string]string `yaml:"types"`
+	// Values - passed to the templates as is, e.g. a package name
+	Values map[string]any `yaml:"values"`
+}
+
+// CodegenTemplateFile - a template of the template directory and the files it renders
+type CodegenTemplateFile struct {
+	Template string `yaml:"template"`
+	// Each - renders a file for every "file" (the named structs and the tables), "table", "struct" or "enum",
+	// or a single file if empty
+	Each string `yaml:"each"`
+	// Name - the template of the file name, e.g. "{{ .File.Name | pascal }}.lua"
+	Name string `yaml:"name"`
+}
+
+func (c *CodegenTemplate) Generate(ctx context.Context, files *FileSink, code *Code) error {
+	if c.TemplateDir == "" {
+		return errors.New("template codegen requires template_dir")
+	}
+
+	for _, file := range c.Files {
+		if file.Template == "" || file.Name == "" {
+			return errors.New("template codegen file requires template and name")
+		}
+
+		var each []*CodeFile
+		switch file.Each {
+		case "":
+			each = []*CodeFile{nil}
+		case "file":
+			for f := range code.Files {
+				each = append(each, f)
+			}
+		case "table":
+			each = code.Tables
+		case "struct":
+			each = code.NamedStructs
+		case "enum":
+			each = code.Enums
+		default:
+			return fmt.Errorf("invalid each of the template: %s, %s", file.Template, file.Each)
+		}
+
+		tmpl, err := template.
+			New(filepath.Base(file.Template)).
+			Funcs(templateFuncMap).
+			Funcs(template.FuncMap{
+				"fieldType":          c.fieldType,
+				"fieldElemType":      c.fieldElemType,
+				"fieldPrimitiveType": c.fieldPrimitiveType,
+			}).
+			ParseFiles(filepath.Join(c.TemplateDir, file.Template))
+		if err != nil {
+			return fmt.Errorf("error parsing template: %s, %w", file.Template, err)
+		}
+		nameTmpl, err := template.New("name").Funcs(templateFuncMap).Parse(file.Name)
+		if err != nil {
+			return fmt.Errorf("error parsing template: %s, %w", file.Name, err)
+		}
+
+		for _, f := range each {
+			values := map[string]any{
+				"File":         f,
+				"Tables":       code.Tables,
+				"NamedStructs": code.NamedStructs,
+				"Enums":        code.Enums,
+				"Values":       c.Values,
+			}
+			if err := c.template(files, file, tmpl, nameTmpl, values); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *CodegenTemplate) template(files *FileSink, file CodegenTemplateFile, tmpl, nameTmpl *template.Template, values map[string]any) error {
+	var name strings.Builder
+	if err := nameTmpl.Execute(&name, values); err != nil {
+		return fmt.Errorf("error executing template: %s, %w", file.Name, err)
+	}
+	fileName := strings.TrimSpace(name.String())
+
+	out, err := files.Create(c.RootDir, fileName, filepath.Ext(fileName))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := tmpl.Execute(out, values); err != nil {
+		return fmt.Errorf("error executing template: %s, %w", fileName, err)
+	}
+	return nil
+}
+
+func (c *CodegenTemplate) fieldType(f *CodeStructField) (string, error) {
+	elemType, err := c.fieldElemType(f)
+	if err != nil {
+		return "", err
+	}
+	if f.IsArray {
+		return c.format("array", elemType), nil
+	}
+	if f.IsNullable && f.Type != FieldTypeJSON {
+		return c.format("nullable", elemType), nil
+	}
+	return elemType, nil
+}
+
+func (c *CodegenTemplate) fieldElemType(f *CodeStructField) (string, error) {
+	if f.Type == FieldTypeStruct {
+		return c.format("struct", pascal(f.StructRef.Name)), nil
+	}
+	if f.Type == FieldTypeEnum {
+		return c.format("enum", pascal(f.EnumRef.Name)), nil
+	}
+	return c.fieldPrimitiveType(f.Type)
+}
+
+func (c *CodegenTemplate) fieldPrimitiveType(typ FieldType) (string, error) {
+	if t, ok := c.Types[string(typ)]; ok {
+		return t, nil
+	}
+	return "", fmt.Errorf("no type of %s in the types of the template codegen", typ)
+}
+
+func (c *CodegenTemplate) format(kind, typ string) string {
+	if format, ok := c.Types[kind]; ok {
+		return strings.ReplaceAll(format, "%s", typ)
+	}
+	return typ
+}
@@ -0,0 +1,74 @@
+package nestcsv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCodegenTemplate(t *testing.T) {
+	templateDir := t.TempDir()
+	for name, content := range map[string]string{
+		"table.lua.tpl": "-- {{ .Values.module }}.{{ .File.Name }}\n" +
+			"{{ range .File.Struct.Fields }}{{ .Name }}: {{ fieldType . }}\n{{ end }}",
+		"init.lua.tpl": "{{ range .Tables }}{{ .Name }}\n{{ end }}{{ range .Enums }}{{ .Name }}\n{{ end }}",
+	} {
+		if err := os.WriteFile(filepath.Join(templateDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	codegen := &CodegenTemplate{
+		RootDir:     t.TempDir(),
+		TemplateDir: templateDir,
+		Files: []CodegenTemplateFile{
+			{Template: "table.lua.tpl", Each: "table", Name: "{{ .File.Name | pascal }}.lua"},
+			{Template: "init.lua.tpl", Name: "init.lua"},
+		},
+		Types: map[string]string{
+			"int": "integer", "long": "integer", "float": "number", "bool": "boolean",
+			"string": "string", "time": "string", "json": "any",
+			"array": "%s[]", "nullable": "%s?", "enum": "enum.%s",
+		},
+		Values: map[string]any{"module": "tables"},
+	}
+	files := generateCodeFiles(t, codegen)
+
+	want := map[string]string{
+		"Items.lua": "-- tables.items\n" +
+			"ID: integer\nName: string\nGrade: enum.Grades?\nShop: string\nTags: string[]\nReward: ItemsReward\nOpenAt: string?\n",
+		"Shops.lua": "-- tables.shops\nID: string\nRate: number\n",
+		"init.lua":  "items\nshops\ngrades\n",
+	}
+	if len(files) != len(want) {
+		t.Errorf("files = %v, want %d", files, len(want))
+	}
+	for name, data := range want {
+		if files[name] != data {
+			t.Errorf("%s = %q, want %q", name, files[name], data)
+		}
+	}
+}
+
+func TestCodegenTemplateMissingType(t *testing.T) {
+	templateDir := t.TempDir()
+	content := "{{ range .File.Struct.Fields }}{{ fieldType . }}\n{{ end }}"
+	if err := os.WriteFile(filepath.Join(templateDir, "table.tpl"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	codegen := &CodegenTemplate{
+		TemplateDir: templateDir,
+		Files:       []CodegenTemplateFile{{Template: "table.tpl", Each: "table", Name: "{{ .File.Name }}.txt"}},
+		Types:       map[string]string{"int": "integer"},
+	}
+	code := &Code{Tables: []*CodeFile{{
+		IsTable: true,
+		Name:    "items",
+		Struct:  &CodeStruct{Name: "items", Fields: []*CodeStructField{{Name: "Name", Type: FieldTypeString}}},
+	}}}
+	err := codegen.Generate(context.Background(), NewFileSink(true), code)
+	if err == nil || !strings.Contains(err.Error(), "no type of string in the types of the template codegen") {
+		t.Errorf("Generate() error = %v, want the missing type", err)
+	}
+}
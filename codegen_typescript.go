@@ -0,0 +1,112 @@
+package nestcsv
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+type CodegenTypeScript struct {
+	RootDir    string `yaml:"root_dir"`
+	FileSuffix string `yaml:"file_suffix"`
+	// ImportExtension - appended to the relative imports, e.g. ".js" for the "NodeNext" module resolution
+	ImportExtension string `yaml:"import_extension"`
+}
+
+func (c *CodegenTypeScript) Generate(ctx context.Context, files *FileSink, code *Code) error {
+	if c.FileSuffix == "" {
+		c.FileSuffix = ".ts"
+	}
+
+	for file := range code.Files {
+		values := map[string]any{
+			"File": file,
+		}
+		if err := c.template(files, file.Name, "file.ts.tpl", values); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range code.Enums {
+		values := map[string]any{
+			"File": file,
+		}
+		if err := c.template(files, file.Name, "enum.ts.tpl", values); err != nil {
+			return err
+		}
+	}
+
+	values := map[string]any{
+		"Tables": code.Tables,
+	}
+	return c.template(files, "nestcsv", "nestcsv.ts.tpl", values)
+}
+
+func (c *CodegenTypeScript) template(files *FileSink, fileName, templateName string, values map[string]any) error {
+	tmpl, err := template.
+		New(filepath.Base(templateName)).
+		Funcs(templateFuncMap).
+		Funcs(template.FuncMap{
+			"fieldType":          c.fieldType,
+			"fieldElemType":      c.fieldElemType,
+			"fieldPrimitiveType": c.fieldPrimitiveType,
+			"importPath": func(name string) string {
+				return "./" + strings.ToLower(name) + c.ImportExtension
+			},
+		}).
+		ParseFS(templateFS, "templates/typescript/"+templateName)
+	if err != nil {
+		return fmt.Errorf("error parsing template: %s, %w", templateName, err)
+	}
+
+	file, err := files.Create(c.RootDir, strings.ToLower(fileName), c.FileSuffix)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := tmpl.Execute(file, values); err != nil {
+		return fmt.Errorf("error executing template: %s, %w", fileName, err)
+	}
+	return nil
+}
+
+func (c *CodegenTypeScript) fieldType(f *CodeStructField) string {
+	if f.IsArray {
+		return c.fieldElemType(f) + "[]"
+	}
+	if f.IsNullable && f.Type != FieldTypeJSON {
+		return c.fieldElemType(f) + " | null"
+	}
+	return c.fieldElemType(f)
+}
+
+func (c *CodegenTypeScript) fieldElemType(f *CodeStructField) string {
+	if f.Type == FieldTypeStruct {
+		return pascal(f.StructRef.Name)
+	}
+	if f.Type == FieldTypeEnum {
+		return pascal(f.EnumRef.Name)
+	}
+	return c.fieldPrimitiveType(f.Type)
+}
+
+func (c *CodegenTypeScript) fieldPrimitiveType(typ FieldType) string {
+	switch typ {
+	case FieldTypeInt, FieldTypeLong, FieldTypeFloat:
+		// a long beyond 2^53 loses the precision as JSON.parse does
+		return "number"
+	case FieldTypeBool:
+		return "boolean"
+	case FieldTypeString:
+		return "string"
+	case FieldTypeTime:
+		return "Date"
+	case FieldTypeJSON:
+		return "unknown"
+	default:
+		panic("unknown type: " + typ)
+	}
+}
@@ -0,0 +1,113 @@
+package nestcsv
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// generateCodeFiles - runs a codegen on the tables covering every kind of field, the files are keyed by the base name
+func generateCodeFiles(t *testing.T, codegen Codegen) map[string]string {
+	t.Helper()
+	config := &Config{
+		Datasources: []DatasourceConfig{NewDatasourceConfig(&DatasourceMemory{Tables: []MemoryTable{
+			{File: "items.csv", Rows: [][]string{
+				{""},
+				{"all", "all", "all", "all", "all", "all", "all", "all"},
+				{"ID", "Name", "Grade", "Shop", "Tags", "Reward.Count", "Reward.Note", "OpenAt"},
+				{"int", "string", "enum:grades?", "ref:shops", "[]string", "long?", "json", "time?"},
+				{""},
+				{"1", "sword", "Rare", "main", "a,b", "", "{}", ""},
+			}},
+			{File: "shops.csv", Rows: [][]string{
+				{"as_map=true"}, {"all", "all"}, {"ID", "Rate"}, {"string", "float"}, {""},
+				{"main", "0.5"},
+			}},
+			{File: "grades.csv", Rows: [][]string{
+				{"as_enum=true"}, {"all"}, {"ID"}, {"string"}, {""},
+				{"Common"}, {"Rare"},
+			}},
+		}})},
+		Codegens: []CodegenConfig{NewCodegenConfig(codegen, "all")},
+	}
+	result, err := Run(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string, len(result.Files))
+	for path, data := range result.Files {
+		if filepath.Base(path) != manifestFileName {
+			files[filepath.Base(path)] = string(data)
+		}
+	}
+	return files
+}
+
+func TestCodegenTypeScript(t *testing.T) {
+	files := generateCodeFiles(t, &CodegenTypeScript{RootDir: t.TempDir(), ImportExtension: ".js"})
+	want := map[string][]string{
+		"items.ts": {
+			`import type { Grades } from "./grades.js";`,
+			"  ID: number;",
+			"  Grade: Grades | null;",
+			"  Shop: string;", // the id type of the referenced table
+			"  Tags: string[];",
+			"  Reward: ItemsReward;",
+			"  OpenAt: Date | null;",
+			"  Count: number | null;",
+			"  Note: unknown;", // a nullable json is not wrapped
+			"    OpenAt: raw.OpenAt == null ? null : new Date(raw.OpenAt),",
+			"  rows: Items[] = [];",
+			"  find(id: number): Items | undefined {",
+		},
+		"shops.ts": {
+			"  rows: Record<string, Shops> = {};",
+			"  find(id: string): Shops | undefined {",
+		},
+		"grades.ts": {
+			`export type Grades = "Common" | "Rare";`,
+		},
+		"nestcsv.ts": {
+			`import { ItemsTable } from "./items.js";`,
+			"  readonly Shops = new ShopsTable();",
+		},
+	}
+	if len(files) != len(want) {
+		t.Errorf("files = %d, want %d", len(files), len(want))
+	}
+	for name, lines := range want {
+		data, ok := files[name]
+		if !ok {
+			t.Errorf("%s is not generated", name)
+			continue
+		}
+		for _, line := range lines {
+			if !strings.Contains(data, line+"\n") {
+				t.Errorf("%s doesn't have %q:\n%s", name, line, data)
+			}
+		}
+	}
+}
+
+func TestCodegenTypeScriptFieldType(t *testing.T) {
+	grades := &CodeEnum{Name: "grades"}
+	reward := &CodeStruct{Name: "items_reward"}
+	tests := []struct {
+		field *CodeStructField
+		want  string
+	}{
+		{field: &CodeStructField{Type: FieldTypeLong}, want: "number"},
+		{field: &CodeStructField{Type: FieldTypeBool, IsNullable: true}, want: "boolean | null"},
+		{field: &CodeStructField{Type: FieldTypeEnum, EnumRef: grades}, want: "Grades"},
+		{field: &CodeStructField{Type: FieldTypeEnum, EnumRef: grades, IsArray: true}, want: "Grades[]"},
+		{field: &CodeStructField{Type: FieldTypeStruct, StructRef: reward, IsArray: true}, want: "ItemsReward[]"},
+		{field: &CodeStructField{Type: FieldTypeJSON, IsNullable: true}, want: "unknown"},
+		{field: &CodeStructField{Type: FieldTypeTime}, want: "Date"},
+	}
+	for _, tt := range tests {
+		if got := (&CodegenTypeScript{}).fieldType(tt.field); got != tt.want {
+			t.Errorf("fieldType(%+v) = %s, want %s", tt.field, got, tt.want)
+		}
+	}
+}
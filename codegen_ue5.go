@@ -2,6 +2,7 @@ package nestcsv
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -13,24 +14,29 @@ type CodegenUE5 struct {
 	RootDir    string `yaml:"root_dir"`
 	Prefix     string `yaml:"prefix"`
 	FileSuffix string `yaml:"file_suffix"`
+	// TemplateDir - overrides the embedded templates by the file name, e.g. "table.h.tpl"
+	TemplateDir string `yaml:"template_dir"`
 }
 
-func (c *CodegenUE5) Generate(code *Code) error {
+func (c *CodegenUE5) Generate(ctx context.Context, files *FileSink, code *Code) error {
 	if c.FileSuffix == "" {
 		c.FileSuffix = ".h"
 	}
 
-	if err := c.template("TableDataBase", "TableDataBase.h.tpl", false, nil); err != nil {
+	if err := c.template(files, "BinReader", "BinReader.h.tpl", false, nil); err != nil {
 		return err
 	}
-	if err := c.template("TableBase", "TableBase.h.tpl", false, nil); err != nil {
+	if err := c.template(files, "TableDataBase", "TableDataBase.h.tpl", false, nil); err != nil {
+		return err
+	}
+	if err := c.template(files, "TableBase", "TableBase.h.tpl", false, nil); err != nil {
 		return err
 	}
 
 	values := map[string]any{
 		"Tables": code.Tables,
 	}
-	if err := c.template("TableHolder", "TableHolder.h.tpl", false, values); err != nil {
+	if err := c.template(files, "TableHolder", "TableHolder.h.tpl", false, values); err != nil {
 		return err
 	}
 
@@ -38,16 +44,25 @@ func (c *CodegenUE5) Generate(code *Code) error {
 		values = map[string]any{
 			"File": file,
 		}
-		if err := c.template(pascal(file.Name), "file.h.tpl", true, values); err != nil {
+		if err := c.template(files, pascal(file.Name), "file.h.tpl", true, values); err != nil {
 			return err
 		}
 
 		if file.IsTable {
-			if err := c.template(pascal(file.Name)+"Table", "table.h.tpl", true, values); err != nil {
+			if err := c.template(files, pascal(file.Name)+"Table", "table.h.tpl", true, values); err != nil {
 				return err
 			}
 		}
 	}
+
+	for _, file := range code.Enums {
+		values = map[string]any{
+			"File": file,
+		}
+		if err := c.template(files, pascal(file.Name), "enum.h.tpl", false, values); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -90,7 +105,7 @@ func (c *CodegenUE5) readExistingFileContent(fileName string) (map[string]any, e
 	return regions, nil
 }
 
-func (c *CodegenUE5) template(fileName, templateName string, withRegions bool, values map[string]any) error {
+func (c *CodegenUE5) template(files *FileSink, fileName, templateName string, withRegions bool, values map[string]any) error {
 	if withRegions {
 		if existingContent, err := c.readExistingFileContent(fileName); err != nil {
 			return err
@@ -101,21 +116,23 @@ func (c *CodegenUE5) template(fileName, templateName string, withRegions bool, v
 		}
 	}
 
-	file, err := createFile(c.RootDir, c.Prefix+fileName, c.FileSuffix)
+	file, err := files.Create(c.RootDir, c.Prefix+fileName, c.FileSuffix)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	tmpl, err := template.
-		New(filepath.Base(templateName)).
-		Funcs(templateFuncMap).
-		Funcs(template.FuncMap{
-			"fieldType":          c.fieldType,
-			"fieldElemType":      c.fieldElemType,
-			"fieldPrimitiveType": c.fieldPrimitiveType,
-		}).
-		ParseFS(templateFS, "templates/ue5/"+templateName)
+	tmpl, err := parseTemplate(
+		template.
+			New(filepath.Base(templateName)).
+			Funcs(templateFuncMap).
+			Funcs(template.FuncMap{
+				"fieldType":          c.fieldType,
+				"fieldElemType":      c.fieldElemType,
+				"fieldPrimitiveType": c.fieldPrimitiveType,
+			}),
+		c.TemplateDir, "ue5", templateName,
+	)
 	if err != nil {
 		return err
 	}
@@ -136,6 +153,9 @@ func (c *CodegenUE5) fieldType(f *CodeStructField) string {
 	if f.IsArray {
 		return "TArray<" + c.fieldElemType(f) + ">"
 	}
+	if f.IsNullable && f.Type != FieldTypeJSON {
+		return "TOptional<" + c.fieldElemType(f) + ">"
+	}
 	return c.fieldElemType(f)
 }
 
@@ -143,6 +163,9 @@ func (c *CodegenUE5) fieldElemType(f *CodeStructField) string {
 	if f.Type == FieldTypeStruct {
 		return "F" + c.Prefix + pascal(f.StructRef.Name)
 	}
+	if f.Type == FieldTypeEnum {
+		return "E" + c.Prefix + pascal(f.EnumRef.Name)
+	}
 	return c.fieldPrimitiveType(f.Type)
 }
 
@@ -1,6 +1,7 @@
 package nestcsv
 
 import (
+	"context"
 	"path/filepath"
 	"text/template"
 )
@@ -14,9 +15,11 @@ type CodegenUnity struct {
 	TableSuffix    string `yaml:"table_suffix"`
 	ResourceFolder string `yaml:"resource_folder"`
 	FileSuffix     string `yaml:"file_suffix"`
+	// TemplateDir - overrides the embedded templates by the file name, e.g. "file.cs.tpl"
+	TemplateDir string `yaml:"template_dir"`
 }
 
-func (c *CodegenUnity) Generate(code *Code) error {
+func (c *CodegenUnity) Generate(ctx context.Context, files *FileSink, code *Code) error {
 	if c.TableSuffix == "" {
 		c.TableSuffix = "Table"
 	}
@@ -25,17 +28,20 @@ func (c *CodegenUnity) Generate(code *Code) error {
 	}
 
 	baseValues := map[string]any{}
-	if err := c.template(c.Prefix+"TableDataBase", "TableDataBase.cs.tpl", baseValues); err != nil {
+	if err := c.template(files, c.Prefix+"TableDataBase", "TableDataBase.cs.tpl", baseValues); err != nil {
 		return err
 	}
-	if err := c.template(c.Prefix+"TableBase", "TableBase.cs.tpl", baseValues); err != nil {
+	if err := c.template(files, c.Prefix+"TableBase", "TableBase.cs.tpl", baseValues); err != nil {
+		return err
+	}
+	if err := c.template(files, c.Prefix+"BinReader", "BinReader.cs.tpl", baseValues); err != nil {
 		return err
 	}
 
 	holderValues := map[string]any{
 		"Tables": code.Tables,
 	}
-	if err := c.template(c.Prefix+"TableHolder", "TableHolder.cs.tpl", holderValues); err != nil {
+	if err := c.template(files, c.Prefix+"TableHolder", "TableHolder.cs.tpl", holderValues); err != nil {
 		return err
 	}
 
@@ -44,29 +50,40 @@ func (c *CodegenUnity) Generate(code *Code) error {
 			"File": file,
 		}
 		className := c.Prefix + pascal(file.Name) + c.DataSuffix
-		if err := c.template(className, "file.cs.tpl", fileValues); err != nil {
+		if err := c.template(files, className, "file.cs.tpl", fileValues); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range code.Enums {
+		enumValues := map[string]any{
+			"File": file,
+		}
+		if err := c.template(files, c.Prefix+pascal(file.Name), "enum.cs.tpl", enumValues); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (c *CodegenUnity) template(fileName, templateName string, values map[string]any) error {
-	file, err := createFile(c.RootDir, fileName, c.FileSuffix)
+func (c *CodegenUnity) template(files *FileSink, fileName, templateName string, values map[string]any) error {
+	file, err := files.Create(c.RootDir, fileName, c.FileSuffix)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	tmpl, err := template.
-		New(filepath.Base(templateName)).
-		Funcs(templateFuncMap).
-		Funcs(template.FuncMap{
-			"fieldType":          c.fieldType,
-			"fieldElemType":      c.fieldElemType,
-			"fieldPrimitiveType": c.fieldPrimitiveType,
-		}).
-		ParseFS(templateFS, "templates/unity/"+templateName)
+	tmpl, err := parseTemplate(
+		template.
+			New(filepath.Base(templateName)).
+			Funcs(templateFuncMap).
+			Funcs(template.FuncMap{
+				"fieldType":          c.fieldType,
+				"fieldElemType":      c.fieldElemType,
+				"fieldPrimitiveType": c.fieldPrimitiveType,
+			}),
+		c.TemplateDir, "unity", templateName,
+	)
 	if err != nil {
 		return err
 	}
@@ -91,6 +108,10 @@ func (c *CodegenUnity) fieldType(f *CodeStructField) string {
 	if f.IsArray {
 		return "List<" + c.fieldElemType(f) + ">"
 	}
+	if f.IsNullable && f.Type != FieldTypeString && f.Type != FieldTypeJSON {
+		// value types only, the others are already nullable
+		return c.fieldElemType(f) + "?"
+	}
 	return c.fieldElemType(f)
 }
 
@@ -98,6 +119,9 @@ func (c *CodegenUnity) fieldElemType(f *CodeStructField) string {
 	if f.Type == FieldTypeStruct {
 		return c.Prefix + pascal(f.StructRef.Name) + c.DataSuffix
 	}
+	if f.Type == FieldTypeEnum {
+		return c.Prefix + pascal(f.EnumRef.Name)
+	}
 	return c.fieldPrimitiveType(f.Type)
 }
 
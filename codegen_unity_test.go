@@ -0,0 +1,46 @@
+package nestcsv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCodegenUnityFieldType(t *testing.T) {
+	rarity := &CodeEnum{Name: "rarity"}
+	tests := []struct {
+		field *CodeStructField
+		want  string
+	}{
+		{field: &CodeStructField{Type: FieldTypeInt}, want: "int"},
+		{field: &CodeStructField{Type: FieldTypeInt, IsNullable: true}, want: "int?"},
+		{field: &CodeStructField{Type: FieldTypeTime, IsNullable: true}, want: "DateTime?"},
+		{field: &CodeStructField{Type: FieldTypeEnum, EnumRef: rarity, IsNullable: true}, want: "Rarity?"},
+		{field: &CodeStructField{Type: FieldTypeString, IsNullable: true}, want: "string"},
+		{field: &CodeStructField{Type: FieldTypeJSON, IsNullable: true}, want: "JToken"},
+		{field: &CodeStructField{Type: FieldTypeInt, IsArray: true}, want: "List<int>"},
+	}
+	for _, tt := range tests {
+		if got := (&CodegenUnity{}).fieldType(tt.field); got != tt.want {
+			t.Errorf("fieldType(%+v) = %s, want %s", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestCodegenUnityTemplateDir(t *testing.T) {
+	templateDir := t.TempDir()
+	override := "namespace {{ $.Namespace }}\n{\n    public enum {{ pascal .File.Name }} { {{ join \", \" .File.Enum.Values }} }\n}\n"
+	if err := os.WriteFile(filepath.Join(templateDir, "enum.cs.tpl"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files := generateCodeFiles(t, &CodegenUnity{RootDir: t.TempDir(), Namespace: "Game.Tables", DataSuffix: "Data", TemplateDir: templateDir})
+
+	if want := "namespace Game.Tables\n{\n    public enum Grades { Common, Rare }\n}\n"; files["Grades.cs"] != want {
+		t.Errorf("Grades.cs = %q, want %q", files["Grades.cs"], want)
+	}
+	// the templates missing in the directory are the embedded ones
+	if !strings.Contains(files["ItemsData.cs"], "class ItemsData") {
+		t.Errorf("ItemsData.cs is not generated by the embedded template:\n%s", files["ItemsData.cs"])
+	}
+}
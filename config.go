@@ -2,7 +2,6 @@ package nestcsv
 
 import (
 	"fmt"
-	"gopkg.in/yaml.v3"
 	"os"
 	"slices"
 )
@@ -11,29 +10,69 @@ type Config struct {
 	Datasources []DatasourceConfig `yaml:"datasources"`
 	Outputs     []OutputConfig     `yaml:"outputs"`
 	Codegens    []CodegenConfig    `yaml:"codegens"`
+
+	// AllErrors - if true, Generate keeps going after a problem and returns every problem found,
+	// which can be collected by CollectTableErrors
+	AllErrors bool `yaml:"all_errors"`
+	// CacheFile - the build cache, e.g. ".nestcsv-cache". The tables which didn't change since the last run
+	// are not marshaled or written again, and the code isn't generated again from the same tables.
+	// The datasources are read and parsed every time
+	CacheFile string `yaml:"cache_file"`
+	// NoPrune - keeps the files which are not generated anymore, instead of removing the ones listed in the manifest
+	// of the root directory
+	NoPrune bool `yaml:"no_prune"`
+
+	// Include - the config files merged under this one, relative to it. The mappings are merged by the key,
+	// and the lists such as the outputs are appended. The paths in the values of an included file, such as
+	// the root_dir or the patterns, are relative to the working directory as the ones of this file are
+	Include []string `yaml:"include"`
+	// Vars - expanded as ${name} in the values, before the environment variables
+	Vars map[string]string `yaml:"vars"`
+	// EnvFiles - the env files read for the ${ENV_VAR} expansion, relative to the config file, in addition to
+	// the .env file next to it. The environment variables win over them
+	EnvFiles []string `yaml:"env_files"`
+
+	files []string // the config files and the env files read, for the watcher
 }
 
+// ParseConfig - reads the config file with its includes, expanding the ${name} variables in the values,
+// and drops the datasources, the outputs and the codegens whose when condition doesn't match
 func ParseConfig(configPath string, args []string) (*Config, error) {
-
-	var config Config
-	file, err := os.Open(configPath)
+	loader := &configLoader{}
+	node, err := loader.load(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %s, %w", configPath, err)
+		return nil, err
+	}
+	if err := loader.loadEnv(configPath, node); err != nil {
+		return nil, err
+	}
+	if vars := mappingValue(node, "vars"); vars != nil {
+		if err := vars.Decode(&loader.vars); err != nil {
+			return nil, fmt.Errorf("failed to decode yaml: %s, vars: %w", configPath, err)
+		}
+	}
+	if err := loader.expand(node); err != nil {
+		return nil, fmt.Errorf("failed to expand the config: %s, %w", configPath, err)
 	}
-	defer file.Close()
 
-	if err := yaml.NewDecoder(file).Decode(&config); err != nil {
+	var config Config
+	if err := node.Decode(&config); err != nil {
 		return nil, fmt.Errorf("failed to decode yaml: %s, %w", configPath, err)
 	}
+	config.files = loader.files
 
+	getenv := func(name string) string {
+		value, _ := loader.getenv(name)
+		return value
+	}
 	config.Datasources = filter(config.Datasources, func(d DatasourceConfig) bool {
-		return d.When == nil || d.When.Match(args)
+		return d.When == nil || d.When.matchEnv(args, getenv)
 	})
 	config.Outputs = filter(config.Outputs, func(e OutputConfig) bool {
-		return e.When == nil || e.When.Match(args)
+		return e.When == nil || e.When.matchEnv(args, getenv)
 	})
 	config.Codegens = filter(config.Codegens, func(c CodegenConfig) bool {
-		return c.When == nil || c.When.Match(args)
+		return c.When == nil || c.When.matchEnv(args, getenv)
 	})
 
 	return &config, nil
@@ -47,12 +86,17 @@ type When struct {
 }
 
 func (w *When) Match(args []string) bool {
-	return w.match(args) != w.Not
+	return w.matchEnv(args, os.Getenv)
+}
+
+// matchEnv - Match with the environment variables of getenv, such as the ones of the env files
+func (w *When) matchEnv(args []string, getenv func(string) string) bool {
+	return w.match(args, getenv) != w.Not
 }
 
-func (w *When) match(args []string) bool {
+func (w *When) match(args []string, getenv func(string) string) bool {
 	for key, value := range w.Env {
-		if os.Getenv(key) != value {
+		if getenv(key) != value {
 			return false
 		}
 	}
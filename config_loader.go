@@ -0,0 +1,271 @@
+package nestcsv
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configLoader - reads a config file with its includes and env files, and expands the variables in its values
+//
+//	${NAME} is replaced with the var of the vars map, or the environment variable, or the one of the env files,
+//	in the order. ${NAME:-default} gives the default if it's unset or empty, and $${NAME} is kept as ${NAME}.
+type configLoader struct {
+	files   []string // every file read, for the watcher
+	loading []string // the include chain, to detect a cycle
+	env     map[string]string
+	vars    map[string]string
+	// resolving - the vars being expanded, to detect a cycle
+	resolving []string
+}
+
+var configVarRegexp = regexp.MustCompile(`\$(\$?)\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// load - the config file merged over its includes
+func (l *configLoader) load(path string) (*yaml.Node, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(l.loading, absPath) {
+		return nil, fmt.Errorf("config include cycle: %s", strings.Join(append(l.loading, absPath), " -> "))
+	}
+	l.loading = append(l.loading, absPath)
+	defer func() { l.loading = l.loading[:len(l.loading)-1] }()
+	l.files = appendUnique(l.files, path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %s, %w", path, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode yaml: %s, %w", path, err)
+	}
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if len(doc.Content) > 0 {
+		node = doc.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to decode yaml: %s, the config is not a mapping", path)
+	}
+
+	var merged *yaml.Node
+	if includes := mappingValue(node, "include"); includes != nil {
+		var paths []string
+		if err := includes.Decode(&paths); err != nil {
+			return nil, fmt.Errorf("failed to decode yaml: %s, include: %w", path, err)
+		}
+		for _, include := range paths {
+			if !filepath.IsAbs(include) {
+				include = filepath.Join(filepath.Dir(path), include)
+			}
+			included, err := l.load(include)
+			if err != nil {
+				return nil, err
+			}
+			merged = mergeConfigNode(merged, included)
+		}
+	}
+	return mergeConfigNode(merged, node), nil
+}
+
+// mergeConfigNode - the mappings are merged by the key and the sequences are appended, otherwise over wins
+func mergeConfigNode(base, over *yaml.Node) *yaml.Node {
+	if base == nil {
+		return over
+	}
+	switch {
+	case base.Kind == yaml.MappingNode && over.Kind == yaml.MappingNode:
+		merged := *base
+		merged.Content = slices.Clone(base.Content)
+		for i := 0; i+1 < len(over.Content); i += 2 {
+			key, value := over.Content[i], over.Content[i+1]
+			if j := mappingIndex(&merged, key.Value); j >= 0 {
+				merged.Content[j+1] = mergeConfigNode(merged.Content[j+1], value)
+			} else {
+				merged.Content = append(merged.Content, key, value)
+			}
+		}
+		return &merged
+	case base.Kind == yaml.SequenceNode && over.Kind == yaml.SequenceNode:
+		merged := *over
+		merged.Content = append(slices.Clone(base.Content), over.Content...)
+		return &merged
+	default:
+		return over
+	}
+}
+
+// mappingIndex - the index of the key in the content of the mapping node, or -1
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if i := mappingIndex(node, key); i >= 0 {
+		return node.Content[i+1]
+	}
+	return nil
+}
+
+// loadEnv - reads the .env file next to the config file if it exists, and the env files of the config
+func (l *configLoader) loadEnv(configPath string, node *yaml.Node) error {
+	l.env = make(map[string]string)
+	paths := []string{filepath.Join(filepath.Dir(configPath), ".env")}
+	if envFiles := mappingValue(node, "env_files"); envFiles != nil {
+		var files []string
+		if err := envFiles.Decode(&files); err != nil {
+			return fmt.Errorf("failed to decode yaml: %s, env_files: %w", configPath, err)
+		}
+		for _, file := range files {
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(filepath.Dir(configPath), file)
+			}
+			paths = append(paths, file)
+		}
+	}
+
+	for i, path := range paths {
+		data, err := os.ReadFile(path)
+		if i == 0 && errors.Is(err, os.ErrNotExist) {
+			// the default .env is optional
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read env file: %s, %w", path, err)
+		}
+		l.files = appendUnique(l.files, path)
+		if err := parseEnvFile(data, l.env); err != nil {
+			return fmt.Errorf("failed to parse env file: %s, %w", path, err)
+		}
+	}
+	return nil
+}
+
+// parseEnvFile - KEY=VALUE lines, with an optional "export", '#' comments and quoted values
+func parseEnvFile(data []byte, env map[string]string) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("line %d: expected KEY=VALUE", n)
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", n, err)
+			}
+			value = unquoted
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+		env[key] = value
+	}
+	return scanner.Err()
+}
+
+// getenv - the environment variable, or the one of the env files
+func (l *configLoader) getenv(name string) (string, bool) {
+	if value, ok := os.LookupEnv(name); ok {
+		return value, true
+	}
+	value, ok := l.env[name]
+	return value, ok
+}
+
+// expand - replaces the variables in every scalar value of the node, so a value is never parsed as yaml again
+func (l *configLoader) expand(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		value, err := l.expandString(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		if value != node.Value {
+			node.Value = value
+			if node.Style == 0 {
+				// a plain value is resolved again, e.g. "${STRICT:-false}" as a bool
+				node.Tag = ""
+			}
+		}
+		return nil
+	}
+	for i, child := range node.Content {
+		// the keys are never expanded
+		if node.Kind == yaml.MappingNode && i%2 == 0 {
+			continue
+		}
+		if err := l.expand(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *configLoader) expandString(s string) (string, error) {
+	var err error
+	expanded := configVarRegexp.ReplaceAllStringFunc(s, func(match string) string {
+		groups := configVarRegexp.FindStringSubmatch(match)
+		if groups[1] != "" {
+			return match[1:]
+		}
+		name, defaultValue, hasDefault := groups[2], groups[3], strings.Contains(match, ":-")
+
+		value, ok, lookupErr := l.lookup(name)
+		if lookupErr != nil {
+			err = lookupErr
+			return match
+		}
+		if hasDefault && value == "" {
+			return defaultValue
+		}
+		if !ok {
+			err = errors.Join(err, fmt.Errorf("undefined variable: %s", name))
+			return match
+		}
+		return value
+	})
+	return expanded, err
+}
+
+// lookup - the var, which can refer to the others, or the environment variable
+func (l *configLoader) lookup(name string) (string, bool, error) {
+	value, ok := l.vars[name]
+	if !ok {
+		value, ok = l.getenv(name)
+		return value, ok, nil
+	}
+	if slices.Contains(l.resolving, name) {
+		return "", false, fmt.Errorf("variable cycle: %s", strings.Join(append(l.resolving, name), " -> "))
+	}
+	l.resolving = append(l.resolving, name)
+	defer func() { l.resolving = l.resolving[:len(l.resolving)-1] }()
+	value, err := l.expandString(value)
+	return value, true, err
+}
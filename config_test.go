@@ -0,0 +1,115 @@
+package nestcsv
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseConfigIncludeVars(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"base.yaml": `
+vars:
+  out: ./out
+outputs:
+  - tags: [client]
+    json:
+      root_dir: ${out}/client
+`,
+		"nestcsv.yaml": `
+include: [base.yaml]
+vars:
+  out: ./build
+datasources:
+  - spreadsheet_gas:
+      url: ${GAS_URL:-http://localhost}
+      password: ${GAS_PASSWORD}
+outputs:
+  - tags: [server]
+    when: {env: {BRANCH: main}}
+    json:
+      root_dir: $${out}
+`,
+		".env": "GAS_PASSWORD='secret'\nBRANCH=main # the default branch\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, err := ParseConfig(filepath.Join(dir, "nestcsv.yaml"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gas := config.Datasources[0].SpreadsheetGAS
+	if gas.URL != "http://localhost" || gas.Password != "secret" {
+		t.Errorf("spreadsheet_gas = %s, %s", gas.URL, gas.Password)
+	}
+	if len(config.Outputs) != 2 {
+		t.Fatalf("outputs = %d, want 2", len(config.Outputs))
+	}
+	if got := config.Outputs[0].JSON.RootDir; got != "./build/client" {
+		t.Errorf("included root_dir = %s", got)
+	}
+	if got := config.Outputs[1].JSON.RootDir; got != "${out}" {
+		t.Errorf("escaped root_dir = %s", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "base.yaml"), []byte("include: [nestcsv.yaml]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseConfig(filepath.Join(dir, "nestcsv.yaml"), nil); err == nil {
+		t.Error("include cycle is not an error")
+	}
+}
+
+func TestParseConfigIncludeSubdir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"shared/base.yaml": `
+include: [nested.yaml]
+datasources:
+  - csv:
+      patterns: [./csv/*.csv]
+`,
+		"shared/nested.yaml": `
+outputs:
+  - tags: [all]
+    json:
+      root_dir: ./out
+`,
+		"nestcsv.yaml": `
+include: [shared/base.yaml]
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, err := ParseConfig(filepath.Join(dir, "nestcsv.yaml"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an include of an included file is relative to it, but the paths in the values are kept relative to the working directory
+	if len(config.Datasources) != 1 || len(config.Outputs) != 1 {
+		t.Fatalf("datasources = %d, outputs = %d, want 1", len(config.Datasources), len(config.Outputs))
+	}
+	if got := config.Datasources[0].CSV.Patterns; len(got) != 1 || got[0] != "./csv/*.csv" {
+		t.Errorf("included patterns = %v", got)
+	}
+	if got := config.Outputs[0].JSON.RootDir; got != "./out" {
+		t.Errorf("included root_dir = %s", got)
+	}
+	if !slices.Contains(config.files, filepath.Join(dir, "shared", "nested.yaml")) {
+		t.Errorf("config files = %v", config.files)
+	}
+}
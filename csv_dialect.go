@@ -0,0 +1,146 @@
+package nestcsv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/transform"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// CSVDialect - the format of the csv files, the zero value is the standard csv in UTF-8
+type CSVDialect struct {
+	// Delimiter - a single character, the default is "," (or a tab for the tsv files)
+	Delimiter string `yaml:"delimiter,omitempty"`
+	// Comment - the lines starting with it are ignored
+	Comment string `yaml:"comment,omitempty"`
+	// LazyQuotes - allows a quote in an unquoted cell and a non-doubled quote in a quoted cell
+	LazyQuotes bool `yaml:"lazy_quotes,omitempty"`
+	// Trim - trims the spaces around every cell
+	Trim bool `yaml:"trim,omitempty"`
+	// Encoding - utf-8 (default), shift_jis, euc-kr (cp949) or cp1252 (windows-1252)
+	Encoding string `yaml:"encoding,omitempty"`
+	// BOM - writes the UTF-8 BOM on saving, the BOM is always stripped on reading
+	BOM bool `yaml:"bom,omitempty"`
+}
+
+// ReadAll - reads every row of a file, the rows can have different lengths
+func (d *CSVDialect) ReadAll(r io.Reader, defaultComma rune) ([][]string, error) {
+	comma, comment, err := d.runes(defaultComma)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := d.encoding()
+	if err != nil {
+		return nil, err
+	}
+	if enc != nil {
+		r = enc.NewDecoder().Reader(r)
+	}
+
+	br := bufio.NewReader(r)
+	if prefix, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+		_, _ = br.Discard(len(utf8BOM))
+	}
+
+	reader := csv.NewReader(br)
+	reader.Comma = comma
+	reader.Comment = comment
+	reader.LazyQuotes = d != nil && d.LazyQuotes
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if d != nil && d.Trim {
+		for _, row := range rows {
+			for i, cell := range row {
+				row[i] = strings.TrimSpace(cell)
+			}
+		}
+	}
+	return rows, nil
+}
+
+// WriteAll - writes the rows in the dialect, so that they can be read back with ReadAll
+func (d *CSVDialect) WriteAll(w io.Writer, defaultComma rune, rows [][]string) error {
+	comma, _, err := d.runes(defaultComma)
+	if err != nil {
+		return err
+	}
+	enc, err := d.encoding()
+	if err != nil {
+		return err
+	}
+
+	if d != nil && d.BOM && enc == nil {
+		if _, err := w.Write(utf8BOM); err != nil {
+			return err
+		}
+	}
+	var encWriter io.WriteCloser
+	if enc != nil {
+		encWriter = transform.NewWriter(w, enc.NewEncoder())
+		w = encWriter
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	if encWriter != nil {
+		return encWriter.Close()
+	}
+	return nil
+}
+
+func (d *CSVDialect) runes(defaultComma rune) (comma, comment rune, err error) {
+	comma = defaultComma
+	if d == nil {
+		return comma, 0, nil
+	}
+	if d.Delimiter != "" {
+		if utf8.RuneCountInString(d.Delimiter) != 1 {
+			return 0, 0, fmt.Errorf("delimiter must be a single character: %q", d.Delimiter)
+		}
+		comma, _ = utf8.DecodeRuneInString(d.Delimiter)
+	}
+	if d.Comment != "" {
+		if utf8.RuneCountInString(d.Comment) != 1 {
+			return 0, 0, fmt.Errorf("comment must be a single character: %q", d.Comment)
+		}
+		comment, _ = utf8.DecodeRuneInString(d.Comment)
+	}
+	return comma, comment, nil
+}
+
+// encoding - returns nil for UTF-8
+func (d *CSVDialect) encoding() (encoding.Encoding, error) {
+	if d == nil {
+		return nil, nil
+	}
+	name := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(d.Encoding))
+	switch name {
+	case "", "utf8":
+		return nil, nil
+	case "shiftjis", "sjis":
+		return japanese.ShiftJIS, nil
+	case "euckr", "cp949":
+		return korean.EUCKR, nil
+	case "cp1252", "windows1252":
+		return charmap.Windows1252, nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %s", d.Encoding)
+	}
+}
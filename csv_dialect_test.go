@@ -0,0 +1,45 @@
+package nestcsv
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCSVDialectRoundTrip(t *testing.T) {
+	cases := []struct {
+		dialect *CSVDialect
+		rows    [][]string
+	}{
+		{nil, [][]string{{"ID", "Name"}, {"1", "item, \"quoted\""}}},
+		{&CSVDialect{BOM: true}, [][]string{{"ID", "Name"}, {"1", "아이템"}}},
+		{&CSVDialect{Delimiter: ";", Encoding: "Shift_JIS"}, [][]string{{"ID", "Name"}, {"1", "アイテム\n二行目"}}},
+		{&CSVDialect{Delimiter: "\t", Encoding: "euc-kr"}, [][]string{{"ID", "Name"}, {"1", "아이템\n두 줄"}}},
+	}
+	for _, c := range cases {
+		var buf bytes.Buffer
+		if err := c.dialect.WriteAll(&buf, ',', c.rows); err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.dialect.ReadAll(&buf, ',')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, c.rows) {
+			t.Errorf("%+v: unexpected rows: %q", c.dialect, got)
+		}
+	}
+}
+
+func TestCSVDialectRead(t *testing.T) {
+	dialect := &CSVDialect{Comment: "#", LazyQuotes: true, Trim: true}
+	got, err := dialect.ReadAll(strings.NewReader("\xEF\xBB\xBFID, Name\n# memo\n1, 5\"x\n2\n"), ',')
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"ID", "Name"}, {"1", "5\"x"}, {"2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected rows: %q", got)
+	}
+}
@@ -1,24 +1,109 @@
 package nestcsv
 
 import (
+	"context"
+	"errors"
 	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"strings"
+	"sync"
 )
 
+// Datasource - collects the tables, the files saved for debugging are created through files
 type Datasource interface {
-	Collect(out chan<- *TableData) error
+	Collect(ctx context.Context, files *FileSink, out chan<- *TableData) error
+}
+
+// FileDatasource - a Datasource reading the local files, which can be collected one by one
+type FileDatasource interface {
+	Datasource
+	FilePatterns() []string
+	CollectFile(ctx context.Context, files *FileSink, path string, out chan<- *TableData) error
+}
+
+// collectFiles - collects the files matched by the patterns concurrently, the errors of every file are joined
+func collectFiles(ctx context.Context, patterns []string, collect func(path string) error) error {
+	ch := make(chan string, 1000)
+	go func() {
+		for path := range glob(patterns) {
+			if skipFile(path) {
+				continue
+			}
+			ch <- path
+		}
+		close(ch)
+	}()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for path := range ch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ctx.Err() != nil {
+				return
+			}
+			if err := collect(path); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(append(errs, ctx.Err())...)
+}
+
+// skipFile - "#" comments a file out, and "~$" is the lock file of a workbook open in Office
+func skipFile(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasPrefix(name, "#") || strings.HasPrefix(name, "~$")
+}
+
+// matchFile - whether the file is collected by the patterns
+func matchFile(patterns []string, path string) bool {
+	if skipFile(path) {
+		return false
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range patterns {
+		pattern, err := filepath.Abs(pattern)
+		if err != nil {
+			continue
+		}
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
 }
 
 type DatasourceConfig struct {
 	When *When `yaml:"when,omitempty"`
 
 	exclusiveConfigGroup[Datasource]
+	Spreadsheet    *DatasourceSpreadsheet    `yaml:"spreadsheet,omitempty"`
 	SpreadsheetGAS *DatasourceSpreadsheetGAS `yaml:"spreadsheet_gas,omitempty"`
 	Excel          *DatasourceExcel          `yaml:"excel,omitempty"`
+	ODS            *DatasourceODS            `yaml:"ods,omitempty"`
 	CSV            *DatasourceCSV            `yaml:"csv,omitempty"`
+	TSV            *DatasourceTSV            `yaml:"tsv,omitempty"`
+	Plugin         *DatasourcePlugin         `yaml:"plugin,omitempty"`
+}
+
+// NewDatasourceConfig - a datasource of the config built in code, such as a DatasourceMemory or a custom one
+func NewDatasourceConfig(d Datasource) DatasourceConfig {
+	return DatasourceConfig{exclusiveConfigGroup: exclusiveConfigGroup[Datasource]{loaded: d}}
 }
 
-func (c *DatasourceConfig) Collect(out chan<- *TableData) error {
-	return c.loaded.Collect(out)
+func (c *DatasourceConfig) Collect(ctx context.Context, files *FileSink, out chan<- *TableData) error {
+	return c.loaded.Collect(ctx, files, out)
 }
 
 func (c *DatasourceConfig) UnmarshalYAML(node *yaml.Node) error {
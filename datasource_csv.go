@@ -1,55 +1,53 @@
 package nestcsv
 
 import (
-	"encoding/csv"
+	"context"
 	"errors"
-	"golang.org/x/sync/errgroup"
+	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 )
 
 type DatasourceCSV struct {
-	Patterns []string `yaml:"patterns"`
+	Patterns   []string `yaml:"patterns"`
+	CSVDialect `yaml:",inline"`
 }
 
-func (d *DatasourceCSV) Collect(out chan<- *TableData) error {
-	ch := make(chan string, 1000)
-	go func() {
-		for path := range glob(d.Patterns) {
-			if strings.HasPrefix(filepath.Base(path), "#") {
-				continue
-			}
-			ch <- path
-		}
-		close(ch)
-	}()
-
-	var wg errgroup.Group
-	for path := range ch {
-		wg.Go(func() error {
-			file, err := os.Open(path)
-			if err != nil {
-				return err
-			}
-			defer file.Close()
-
-			rows, err := csv.NewReader(file).ReadAll()
-			if err != nil {
-				return err
-			}
-
-			tableData, err := ParseTableData(path, rows)
-			if err != nil {
-				if errors.Is(err, ErrSkipTable) {
-					return nil
-				}
-				return err
-			}
-
-			out <- tableData
+func (d *DatasourceCSV) Collect(ctx context.Context, files *FileSink, out chan<- *TableData) error {
+	return collectFiles(ctx, d.Patterns, func(path string) error {
+		return d.CollectFile(ctx, files, path, out)
+	})
+}
+
+func (d *DatasourceCSV) FilePatterns() []string {
+	return d.Patterns
+}
+
+func (d *DatasourceCSV) CollectFile(ctx context.Context, files *FileSink, path string, out chan<- *TableData) error {
+	return collectDelimitedFile(path, &d.CSVDialect, ',', out)
+}
+
+// collectDelimitedFile - reads a csv-like file in the dialect, a cell can be quoted to contain new lines
+func collectDelimitedFile(path string, dialect *CSVDialect, defaultComma rune, out chan<- *TableData) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	rows, err := dialect.ReadAll(file, defaultComma)
+	if err != nil {
+		return fmt.Errorf("failed to read the file: %s, %w", path, err)
+	}
+	rows = padRows(rows, 0)
+
+	tableData, err := ParseTableData(path, "", rows)
+	if err != nil {
+		if errors.Is(err, ErrSkipTable) {
 			return nil
-		})
+		}
+		return err
 	}
-	return wg.Wait()
+
+	out <- tableData
+	return nil
 }
@@ -1,65 +1,62 @@
 package nestcsv
 
 import (
+	"context"
 	"errors"
 	"github.com/xuri/excelize/v2"
-	"golang.org/x/sync/errgroup"
-	"path/filepath"
 	"strings"
 )
 
 type DatasourceExcel struct {
-	Patterns     []string `yaml:"patterns"`
-	DebugSaveDir *string  `yaml:"debug_save_dir,omitempty"`
+	Patterns         []string    `yaml:"patterns"`
+	DebugSaveDir     *string     `yaml:"debug_save_dir,omitempty"`
+	DebugSaveDialect *CSVDialect `yaml:"debug_save_dialect,omitempty"`
 }
 
-func (d *DatasourceExcel) Collect(out chan<- *TableData) error {
-	ch := make(chan string, 1000)
-	go func() {
-		for path := range glob(d.Patterns) {
-			if strings.HasPrefix(filepath.Base(path), "#") {
-				continue
-			}
-			ch <- path
-		}
-		close(ch)
-	}()
+func (d *DatasourceExcel) Collect(ctx context.Context, files *FileSink, out chan<- *TableData) error {
+	return collectFiles(ctx, d.Patterns, func(path string) error {
+		return d.CollectFile(ctx, files, path, out)
+	})
+}
 
-	var wg errgroup.Group
-	for path := range ch {
-		wg.Go(func() error {
-			file, err := excelize.OpenFile(path)
-			if err != nil {
-				return err
-			}
-			defer file.Close()
+func (d *DatasourceExcel) FilePatterns() []string {
+	return d.Patterns
+}
+
+func (d *DatasourceExcel) CollectFile(ctx context.Context, files *FileSink, path string, out chan<- *TableData) error {
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
 
-			for _, sheet := range file.GetSheetList() {
-				if strings.HasPrefix(sheet, "#") {
-					continue
-				}
+	var errs []error
+	for _, sheet := range file.GetSheetList() {
+		if strings.HasPrefix(sheet, "#") {
+			continue
+		}
 
-				rows, err := file.GetRows(sheet)
-				if err != nil {
-					return err
-				}
+		rows, err := file.GetRows(sheet)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// excelize trims the trailing empty cells and rows
+		rows = padTableRows(rows)
 
-				tableData, err := ParseTableData(sheet, rows)
-				if err != nil {
-					if errors.Is(err, ErrSkipTable) {
-						return nil
-					}
-					return err
-				}
-				if d.DebugSaveDir != nil {
-					if err := saveCSVFile(*d.DebugSaveDir, sheet, rows); err != nil {
-						return err
-					}
-				}
-				out <- tableData
+		tableData, err := ParseTableData(path, sheet, rows)
+		if err != nil {
+			if !errors.Is(err, ErrSkipTable) {
+				errs = append(errs, err)
 			}
-			return nil
-		})
+			continue
+		}
+		if d.DebugSaveDir != nil {
+			if err := saveCSVFile(files, *d.DebugSaveDir, sheet, rows, d.DebugSaveDialect); err != nil {
+				return err
+			}
+		}
+		out <- tableData
 	}
-	return wg.Wait()
+	return errors.Join(errs...)
 }
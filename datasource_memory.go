@@ -0,0 +1,63 @@
+package nestcsv
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// DatasourceMemory - collects the tables given in memory, to run nestcsv as a library. It's not in the yaml config,
+// use NewDatasourceConfig
+type DatasourceMemory struct {
+	Tables []MemoryTable
+}
+
+// MemoryTable - the rows of a sheet with the header rows, or a reader of them
+type MemoryTable struct {
+	// File, Sheet - where the table comes from, it's named after the sheet, or the file if the sheet is empty
+	File   string
+	Sheet  string
+	Rows   [][]string
+	Reader io.Reader // read if Rows is nil, as a tsv if the file has the ".tsv" extension, otherwise a csv
+	// Dialect - optional, the dialect of the Reader
+	Dialect *CSVDialect
+}
+
+func (d *DatasourceMemory) Collect(ctx context.Context, files *FileSink, out chan<- *TableData) error {
+	var errs []error
+	for _, table := range d.Tables {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if table.File == "" && table.Sheet == "" {
+			return errors.New("memory table requires file or sheet")
+		}
+
+		rows := table.Rows
+		if rows == nil && table.Reader != nil {
+			comma := ','
+			if strings.EqualFold(filepath.Ext(table.File), ".tsv") {
+				comma = '\t'
+			}
+			var err error
+			if rows, err = table.Dialect.ReadAll(table.Reader, comma); err != nil {
+				errs = append(errs, fmt.Errorf("failed to read the table: %s, %w", cmp.Or(table.Sheet, table.File), err))
+				continue
+			}
+		}
+
+		tableData, err := ParseTableData(table.File, table.Sheet, padRows(rows, 0))
+		if err != nil {
+			if !errors.Is(err, ErrSkipTable) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		out <- tableData
+	}
+	return errors.Join(errs...)
+}
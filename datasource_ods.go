@@ -0,0 +1,248 @@
+package nestcsv
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	odsOfficeNS = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odsTableNS  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odsTextNS   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+)
+
+// DatasourceODS - reads the OpenDocument spreadsheets (e.g. saved by LibreOffice), every sheet is a table
+type DatasourceODS struct {
+	Patterns         []string    `yaml:"patterns"`
+	DebugSaveDir     *string     `yaml:"debug_save_dir,omitempty"`
+	DebugSaveDialect *CSVDialect `yaml:"debug_save_dialect,omitempty"`
+}
+
+func (d *DatasourceODS) Collect(ctx context.Context, files *FileSink, out chan<- *TableData) error {
+	return collectFiles(ctx, d.Patterns, func(path string) error {
+		return d.CollectFile(ctx, files, path, out)
+	})
+}
+
+func (d *DatasourceODS) FilePatterns() []string {
+	return d.Patterns
+}
+
+func (d *DatasourceODS) CollectFile(ctx context.Context, files *FileSink, path string, out chan<- *TableData) error {
+	sheets, err := readODSFile(path)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, sheet := range sheets {
+		if strings.HasPrefix(sheet.title, "#") {
+			continue
+		}
+
+		tableData, err := ParseTableData(path, sheet.title, sheet.rows)
+		if err != nil {
+			if !errors.Is(err, ErrSkipTable) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if d.DebugSaveDir != nil {
+			if err := saveCSVFile(files, *d.DebugSaveDir, sheet.title, sheet.rows, d.DebugSaveDialect); err != nil {
+				return err
+			}
+		}
+		out <- tableData
+	}
+	return errors.Join(errs...)
+}
+
+func readODSFile(path string) ([]*sheetValues, error) {
+	zipReader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the ods file: %s, %w", path, err)
+	}
+	defer zipReader.Close()
+
+	content, err := zipReader.Open("content.xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the content of the ods file: %s, %w", path, err)
+	}
+	defer content.Close()
+
+	sheets, err := parseODSContent(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the ods file: %s, %w", path, err)
+	}
+	return sheets, nil
+}
+
+// parseODSContent - reads the sheets from content.xml
+//
+//	The repeated rows and cells are expanded, except the trailing empty ones
+//	which fill the sheet up to the maximum size.
+func parseODSContent(r io.Reader) ([]*sheetValues, error) {
+	var (
+		decoder = xml.NewDecoder(r)
+		sheets  = make([]*sheetValues, 0)
+
+		sheet        *sheetValues
+		row          []string
+		rowRepeat    int
+		pendingRows  int
+		pendingCells int
+
+		inCell     bool
+		cellRepeat int
+		cellValue  string
+		cellText   strings.Builder
+		paragraphs int
+	)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == odsTableNS && t.Name.Local == "table":
+				sheet = &sheetValues{title: odsAttr(t, odsTableNS, "name")}
+				pendingRows = 0
+
+			case t.Name.Space == odsTableNS && t.Name.Local == "table-row":
+				row = make([]string, 0)
+				rowRepeat = odsRepeat(t, "number-rows-repeated")
+				pendingCells = 0
+
+			case t.Name.Space == odsTableNS && (t.Name.Local == "table-cell" || t.Name.Local == "covered-table-cell"):
+				inCell = true
+				cellRepeat = odsRepeat(t, "number-columns-repeated")
+				cellValue = odsCellValue(t)
+				cellText.Reset()
+				paragraphs = 0
+
+			case !inCell:
+
+			case t.Name.Space == odsOfficeNS && t.Name.Local == "annotation":
+				// comments are not a part of the value
+				if err := decoder.Skip(); err != nil {
+					return nil, err
+				}
+
+			case t.Name.Space == odsTextNS && t.Name.Local == "p":
+				if paragraphs > 0 {
+					cellText.WriteByte('\n')
+				}
+				paragraphs++
+
+			case t.Name.Space == odsTextNS && t.Name.Local == "s":
+				count, _ := strconv.Atoi(odsAttr(t, odsTextNS, "c"))
+				cellText.WriteString(strings.Repeat(" ", max(count, 1)))
+
+			case t.Name.Space == odsTextNS && t.Name.Local == "tab":
+				cellText.WriteByte('\t')
+
+			case t.Name.Space == odsTextNS && t.Name.Local == "line-break":
+				cellText.WriteByte('\n')
+			}
+
+		case xml.CharData:
+			if inCell && paragraphs > 0 {
+				cellText.Write(t)
+			}
+
+		case xml.EndElement:
+			switch {
+			case t.Name.Space == odsTableNS && (t.Name.Local == "table-cell" || t.Name.Local == "covered-table-cell"):
+				inCell = false
+				value := cellValue
+				if value == "" {
+					value = cellText.String()
+				}
+				if value == "" {
+					pendingCells += cellRepeat
+					break
+				}
+				for ; pendingCells > 0; pendingCells-- {
+					row = append(row, "")
+				}
+				for i := 0; i < cellRepeat; i++ {
+					row = append(row, value)
+				}
+
+			case t.Name.Space == odsTableNS && t.Name.Local == "table-row":
+				if sheet == nil {
+					break
+				}
+				if len(row) == 0 {
+					pendingRows += rowRepeat
+					break
+				}
+				for ; pendingRows > 0; pendingRows-- {
+					sheet.rows = append(sheet.rows, []string{})
+				}
+				for i := 0; i < rowRepeat; i++ {
+					sheet.rows = append(sheet.rows, append([]string{}, row...))
+				}
+
+			case t.Name.Space == odsTableNS && t.Name.Local == "table":
+				if sheet == nil {
+					break
+				}
+				sheet.rows = padTableRows(sheet.rows)
+				sheets = append(sheets, sheet)
+				sheet = nil
+			}
+		}
+	}
+	return sheets, nil
+}
+
+func odsAttr(t xml.StartElement, space, local string) string {
+	for _, attr := range t.Attr {
+		if attr.Name.Space == space && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func odsRepeat(t xml.StartElement, local string) int {
+	n, err := strconv.Atoi(odsAttr(t, odsTableNS, local))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// odsCellValue - returns the raw value of a typed cell, or an empty string to use the displayed text
+func odsCellValue(t xml.StartElement) string {
+	switch odsAttr(t, odsOfficeNS, "value-type") {
+	case "float", "percentage", "currency":
+		return odsAttr(t, odsOfficeNS, "value")
+	case "boolean":
+		return odsAttr(t, odsOfficeNS, "boolean-value")
+	case "date":
+		value := odsAttr(t, odsOfficeNS, "date-value")
+		for _, layout := range []string{"2006-01-02T15:04:05", time.DateOnly} {
+			if date, err := time.Parse(layout, value); err == nil {
+				return date.Format(time.DateTime)
+			}
+		}
+		return ""
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,71 @@
+package nestcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseODSContent(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"><office:body><office:spreadsheet>
+<table:table table:name="items">
+<table:table-row><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+<table:table-row><table:table-cell table:number-columns-repeated="2"><text:p>all</text:p></table:table-cell><table:table-cell table:number-columns-repeated="1022"/></table:table-row>
+<table:table-row><table:table-cell><text:p>ID</text:p></table:table-cell><table:table-cell><text:p>[]SKU.</text:p><text:p>ID</text:p></table:table-cell></table:table-row>
+<table:table-row><table:table-cell><text:p>int</text:p></table:table-cell><table:table-cell><text:p>string</text:p></table:table-cell></table:table-row>
+<table:table-row table:number-rows-repeated="2"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+<table:table-row><table:table-cell office:value-type="float" office:value="1"><text:p>1.00</text:p><office:annotation><text:p>memo</text:p></office:annotation></table:table-cell><table:table-cell><text:p>a<text:s text:c="2"/>b</text:p></table:table-cell></table:table-row>
+<table:table-row table:number-rows-repeated="1048570"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+</table:table>
+</office:spreadsheet></office:body></office:document-content>`
+
+	sheets, err := parseODSContent(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sheets) != 1 || sheets[0].title != "items" {
+		t.Fatalf("unexpected sheets: %v", sheets)
+	}
+	want := [][]string{
+		{"", ""},
+		{"all", "all"},
+		{"ID", "[]SKU.\nID"},
+		{"int", "string"},
+		{"", ""},
+		{"", ""},
+		{"1", "a  b"},
+	}
+	if !reflect.DeepEqual(sheets[0].rows, want) {
+		t.Errorf("unexpected rows: %q", sheets[0].rows)
+	}
+}
+
+func TestParseODSContentConstraintRow(t *testing.T) {
+	// the empty constraint row of a sheet without data is trailing, so it's not written
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"><office:body><office:spreadsheet>
+<table:table table:name="items">
+<table:table-row><table:table-cell><text:p>constraint_row=true</text:p></table:table-cell></table:table-row>
+<table:table-row><table:table-cell><text:p>all</text:p></table:table-cell></table:table-row>
+<table:table-row><table:table-cell><text:p>ID</text:p></table:table-cell></table:table-row>
+<table:table-row><table:table-cell><text:p>int</text:p></table:table-cell></table:table-row>
+<table:table-row table:number-rows-repeated="1048572"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+</table:table>
+</office:spreadsheet></office:body></office:document-content>`
+
+	sheets, err := parseODSContent(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sheets) != 1 || len(sheets[0].rows) != TableConstraintRow+1 {
+		t.Fatalf("unexpected sheets: %v", sheets)
+	}
+	td, err := ParseTableData("items.ods", sheets[0].title, sheets[0].rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(td.DataRows) != 0 || len(td.FieldConstraints) != 1 {
+		t.Errorf("unexpected table: %v, %v", td.DataRows, td.FieldConstraints)
+	}
+}
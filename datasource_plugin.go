@@ -0,0 +1,47 @@
+package nestcsv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DatasourcePlugin - collects the tables from a plugin
+//
+//	The request is {"kind": "datasource", "options": {...}}, and the response is
+//	{"tables": [{"file": "items.xlsx", "sheet": "Items", "rows": [["...", ...], ...]}]}, where the rows are
+//	the cells of a sheet as is, with the header rows. A table is named after the sheet, or the file if it's empty.
+type DatasourcePlugin struct {
+	Plugin `yaml:",inline"`
+}
+
+type datasourcePluginResponse struct {
+	Tables []struct {
+		File  string     `json:"file"`
+		Sheet string     `json:"sheet"`
+		Rows  [][]string `json:"rows"`
+	} `json:"tables"`
+}
+
+func (d *DatasourcePlugin) Collect(ctx context.Context, files *FileSink, out chan<- *TableData) error {
+	var response datasourcePluginResponse
+	if err := d.call(ctx, "datasource", nil, &response); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, table := range response.Tables {
+		if table.File == "" && table.Sheet == "" {
+			return fmt.Errorf("plugin table requires file or sheet: %s", d.Command)
+		}
+		tableData, err := ParseTableData(table.File, table.Sheet, padRows(table.Rows, 0))
+		if err != nil {
+			if !errors.Is(err, ErrSkipTable) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		out <- tableData
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,379 @@
+package nestcsv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	spreadsheetDefaultBaseURL      = "https://sheets.googleapis.com"
+	spreadsheetDefaultDriveBaseURL = "https://www.googleapis.com"
+	spreadsheetDefaultTokenURL     = "https://oauth2.googleapis.com/token"
+
+	googleSpreadsheetMimeType = "application/vnd.google-apps.spreadsheet"
+	googleFolderMimeType      = "application/vnd.google-apps.folder"
+)
+
+var spreadsheetScopes = []string{
+	"https://www.googleapis.com/auth/spreadsheets.readonly",
+	"https://www.googleapis.com/auth/drive.metadata.readonly",
+}
+
+// DatasourceSpreadsheet - reads the spreadsheets with the Google Sheets API, without deploying an Apps Script
+type DatasourceSpreadsheet struct {
+	GoogleDriveFolderIDs []string `yaml:"google_drive_folder_ids"`
+	SpreadsheetFileIDs   []string `yaml:"spreadsheet_file_ids"`
+
+	// ServiceAccountFile - the json key of a service account, the spreadsheets and folders must be shared with it
+	ServiceAccountFile *string `yaml:"service_account_file,omitempty"`
+	// OAuth2 - the credentials of a user, used if ServiceAccountFile is not set
+	OAuth2 *SpreadsheetOAuth2 `yaml:"oauth2,omitempty"`
+
+	// BaseURL - replaces the Sheets and Drive API hosts, e.g. to test against a local fake server,
+	// and the token url with its /token, unless oauth2.token_url is set
+	BaseURL          *string     `yaml:"base_url,omitempty"`
+	DebugSaveDir     *string     `yaml:"debug_save_dir,omitempty"`
+	DebugSaveDialect *CSVDialect `yaml:"debug_save_dialect,omitempty"`
+}
+
+type SpreadsheetOAuth2 struct {
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	RefreshToken string `yaml:"refresh_token"`
+	TokenURL     string `yaml:"token_url,omitempty"`
+}
+
+func (d *DatasourceSpreadsheet) Collect(ctx context.Context, files *FileSink, out chan<- *TableData) error {
+	client, err := d.httpClient(ctx)
+	if err != nil {
+		return err
+	}
+	api := &sheetsAPI{
+		client:       client,
+		baseURL:      spreadsheetDefaultBaseURL,
+		driveBaseURL: spreadsheetDefaultDriveBaseURL,
+	}
+	if d.BaseURL != nil {
+		api.baseURL = strings.TrimSuffix(*d.BaseURL, "/")
+		api.driveBaseURL = api.baseURL
+	}
+
+	fileIDs := make([]string, 0, len(d.SpreadsheetFileIDs))
+	fileIDs = append(fileIDs, d.SpreadsheetFileIDs...)
+	for _, folderID := range d.GoogleDriveFolderIDs {
+		ids, err := api.listSpreadsheets(ctx, folderID)
+		if err != nil {
+			return err
+		}
+		fileIDs = append(fileIDs, ids...)
+	}
+
+	// the errors of every spreadsheet and sheet are joined
+	errs := make([][]error, len(fileIDs))
+	var wg sync.WaitGroup
+	for i, fileID := range fileIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			spreadsheet, sheets, err := api.readSpreadsheet(ctx, fileID)
+			if err != nil {
+				errs[i] = append(errs[i], err)
+				return
+			}
+			for _, sheet := range sheets {
+				tableData, err := ParseTableData(spreadsheet, sheet.title, sheet.rows)
+				if err != nil {
+					if !errors.Is(err, ErrSkipTable) {
+						errs[i] = append(errs[i], err)
+					}
+					continue
+				}
+				if d.DebugSaveDir != nil {
+					if err := saveCSVFile(files, *d.DebugSaveDir, sheet.title, sheet.rows, d.DebugSaveDialect); err != nil {
+						errs[i] = append(errs[i], err)
+						return
+					}
+				}
+				out <- tableData
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(slices.Concat(errs...)...)
+}
+
+func (d *DatasourceSpreadsheet) httpClient(ctx context.Context) (*http.Client, error) {
+	tokenURL := spreadsheetDefaultTokenURL
+	if d.BaseURL != nil {
+		tokenURL = strings.TrimSuffix(*d.BaseURL, "/") + "/token"
+	}
+
+	switch {
+	case d.ServiceAccountFile != nil:
+		keyJSON, err := os.ReadFile(*d.ServiceAccountFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the service account file: %s, %w", *d.ServiceAccountFile, err)
+		}
+		var key struct {
+			ClientEmail  string `json:"client_email"`
+			PrivateKey   string `json:"private_key"`
+			PrivateKeyID string `json:"private_key_id"`
+			TokenURI     string `json:"token_uri"`
+		}
+		if err := json.Unmarshal(keyJSON, &key); err != nil {
+			return nil, fmt.Errorf("failed to decode the service account file: %s, %w", *d.ServiceAccountFile, err)
+		}
+		config := &jwt.Config{
+			Email:        key.ClientEmail,
+			PrivateKey:   []byte(key.PrivateKey),
+			PrivateKeyID: key.PrivateKeyID,
+			Scopes:       spreadsheetScopes,
+			TokenURL:     key.TokenURI,
+		}
+		if config.TokenURL == "" || d.BaseURL != nil {
+			config.TokenURL = tokenURL
+		}
+		return config.Client(ctx), nil
+
+	case d.OAuth2 != nil:
+		config := &oauth2.Config{
+			ClientID:     d.OAuth2.ClientID,
+			ClientSecret: d.OAuth2.ClientSecret,
+			Endpoint:     oauth2.Endpoint{TokenURL: d.OAuth2.TokenURL},
+			Scopes:       spreadsheetScopes,
+		}
+		if config.Endpoint.TokenURL == "" {
+			config.Endpoint.TokenURL = tokenURL
+		}
+		return config.Client(ctx, &oauth2.Token{RefreshToken: d.OAuth2.RefreshToken}), nil
+
+	default:
+		if d.BaseURL == nil {
+			return nil, fmt.Errorf("spreadsheet: service_account_file or oauth2 is required")
+		}
+		// a fake server doesn't need to be authorized
+		return http.DefaultClient, nil
+	}
+}
+
+type sheetsAPI struct {
+	client       *http.Client
+	baseURL      string
+	driveBaseURL string
+}
+
+type sheetValues struct {
+	title string
+	rows  [][]string
+}
+
+// listSpreadsheets - returns the ids of the spreadsheets in the folder and its subfolders
+func (a *sheetsAPI) listSpreadsheets(ctx context.Context, folderID string) ([]string, error) {
+	var (
+		ids       = make([]string, 0)
+		pageToken string
+	)
+	for {
+		query := url.Values{
+			"q":                         {fmt.Sprintf("'%s' in parents and trashed = false", folderID)},
+			"fields":                    {"nextPageToken,files(id,mimeType)"},
+			"pageSize":                  {"1000"},
+			"supportsAllDrives":         {"true"},
+			"includeItemsFromAllDrives": {"true"},
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		var res struct {
+			NextPageToken string `json:"nextPageToken"`
+			Files         []struct {
+				ID       string `json:"id"`
+				MimeType string `json:"mimeType"`
+			} `json:"files"`
+		}
+		if err := a.get(ctx, a.driveBaseURL+"/drive/v3/files", query, &res); err != nil {
+			return nil, fmt.Errorf("failed to list the folder: %s, %w", folderID, err)
+		}
+
+		for _, file := range res.Files {
+			switch file.MimeType {
+			case googleSpreadsheetMimeType:
+				ids = append(ids, file.ID)
+			case googleFolderMimeType:
+				subIDs, err := a.listSpreadsheets(ctx, file.ID)
+				if err != nil {
+					return nil, err
+				}
+				ids = append(ids, subIDs...)
+			}
+		}
+
+		if res.NextPageToken == "" {
+			return ids, nil
+		}
+		pageToken = res.NextPageToken
+	}
+}
+
+// readSpreadsheet - returns the title of the spreadsheet and the values of its sheets, except the ones starting with #
+func (a *sheetsAPI) readSpreadsheet(ctx context.Context, fileID string) (string, []*sheetValues, error) {
+	var meta struct {
+		Properties struct {
+			Title string `json:"title"`
+		} `json:"properties"`
+		Sheets []struct {
+			Properties struct {
+				Title string `json:"title"`
+			} `json:"properties"`
+			Data []sheetGridFormats `json:"data"`
+		} `json:"sheets"`
+	}
+	spreadsheetURL := a.baseURL + "/v4/spreadsheets/" + url.PathEscape(fileID)
+	// the number formats tell the dates from the numbers, since both are read as serial numbers
+	metaFields := "properties.title,sheets(properties.title,data.rowData.values.effectiveFormat.numberFormat.type)"
+	if err := a.get(ctx, spreadsheetURL, url.Values{"fields": {metaFields}}, &meta); err != nil {
+		return "", nil, fmt.Errorf("failed to get the spreadsheet: %s, %w", fileID, err)
+	}
+
+	query := url.Values{
+		"majorDimension":       {"ROWS"},
+		"valueRenderOption":    {"UNFORMATTED_VALUE"},
+		"dateTimeRenderOption": {"SERIAL_NUMBER"},
+	}
+	for _, sheet := range meta.Sheets {
+		if title := sheet.Properties.Title; !strings.HasPrefix(title, "#") {
+			// quote the title, since it can contain spaces or look like an A1 range
+			query.Add("ranges", "'"+strings.ReplaceAll(title, "'", "''")+"'")
+		}
+	}
+	sheets := make([]*sheetValues, 0, len(query["ranges"]))
+	if len(query["ranges"]) == 0 {
+		return meta.Properties.Title, sheets, nil
+	}
+
+	var res struct {
+		ValueRanges []struct {
+			Values [][]any `json:"values"`
+		} `json:"valueRanges"`
+	}
+	if err := a.get(ctx, spreadsheetURL+"/values:batchGet", query, &res); err != nil {
+		return "", nil, fmt.Errorf("failed to get the values: %s, %w", meta.Properties.Title, err)
+	}
+	if len(res.ValueRanges) != len(query["ranges"]) {
+		return "", nil, fmt.Errorf("unexpected number of value ranges: %s, %d", meta.Properties.Title, len(res.ValueRanges))
+	}
+
+	i := 0
+	for _, sheet := range meta.Sheets {
+		if strings.HasPrefix(sheet.Properties.Title, "#") {
+			continue
+		}
+		var formats sheetGridFormats
+		if len(sheet.Data) > 0 {
+			formats = sheet.Data[0]
+		}
+		rows := make([][]string, len(res.ValueRanges[i].Values))
+		for rowIdx, row := range res.ValueRanges[i].Values {
+			rows[rowIdx] = make([]string, len(row))
+			for col, value := range row {
+				if serial, ok := value.(float64); ok && formats.isDate(rowIdx, col) {
+					rows[rowIdx][col] = formatSheetSerial(serial)
+				} else {
+					rows[rowIdx][col] = formatSheetValue(value)
+				}
+			}
+		}
+
+		sheets = append(sheets, &sheetValues{
+			title: sheet.Properties.Title,
+			// the api trims the trailing empty cells and rows
+			rows: padTableRows(rows),
+		})
+		i++
+	}
+	return meta.Properties.Title, sheets, nil
+}
+
+func (a *sheetsAPI) get(ctx context.Context, uri string, query url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	res, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%s: %s", res.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode the response: %w", err)
+	}
+	return nil
+}
+
+// sheetGridFormats - the number formats of the cells of a sheet
+type sheetGridFormats struct {
+	RowData []struct {
+		Values []struct {
+			EffectiveFormat struct {
+				NumberFormat struct {
+					Type string `json:"type"`
+				} `json:"numberFormat"`
+			} `json:"effectiveFormat"`
+		} `json:"values"`
+	} `json:"rowData"`
+}
+
+func (f sheetGridFormats) isDate(row, col int) bool {
+	if row >= len(f.RowData) || col >= len(f.RowData[row].Values) {
+		return false
+	}
+	switch f.RowData[row].Values[col].EffectiveFormat.NumberFormat.Type {
+	case "DATE", "TIME", "DATE_TIME":
+		return true
+	default:
+		return false
+	}
+}
+
+// formatSheetSerial - formats a serial number, the days since 1899-12-30 in the time zone of the spreadsheet,
+// as the Apps Script datasource formats a date in the time zone of the spreadsheet
+func formatSheetSerial(serial float64) string {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	return epoch.Add(time.Duration(math.Round(serial*24*60*60)) * time.Second).Format(time.DateTime)
+}
+
+// formatSheetValue - formats an unformatted value as the Apps Script datasource does
+func formatSheetValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
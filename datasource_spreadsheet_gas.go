@@ -3,29 +3,32 @@ package nestcsv
 import (
 	"archive/zip"
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/csv"
 	"errors"
 	"fmt"
-	"golang.org/x/sync/errgroup"
 	"io"
 	"net/http"
 	"net/url"
+	"path"
 	"strings"
+	"sync"
 )
 
 type DatasourceSpreadsheetGAS struct {
-	URL                  string   `yaml:"url"`
-	Password             string   `yaml:"password"`
-	GoogleDriveFolderIDs []string `yaml:"google_drive_folder_ids"`
-	SpreadsheetFileIDs   []string `yaml:"spreadsheet_file_ids"`
-	DebugSaveDir         *string  `yaml:"debug_save_dir,omitempty"`
+	URL                  string      `yaml:"url"`
+	Password             string      `yaml:"password"`
+	GoogleDriveFolderIDs []string    `yaml:"google_drive_folder_ids"`
+	SpreadsheetFileIDs   []string    `yaml:"spreadsheet_file_ids"`
+	DebugSaveDir         *string     `yaml:"debug_save_dir,omitempty"`
+	DebugSaveDialect     *CSVDialect `yaml:"debug_save_dialect,omitempty"`
 
 	// TODO : add google oauth2 authentication
 }
 
-func (d *DatasourceSpreadsheetGAS) Collect(out chan<- *TableData) error {
-	zipData, err := d.callGASAndReadBase64()
+func (d *DatasourceSpreadsheetGAS) Collect(ctx context.Context, files *FileSink, out chan<- *TableData) error {
+	zipData, err := d.callGASAndReadBase64(ctx)
 	if err != nil {
 		return err
 	}
@@ -38,7 +41,7 @@ func (d *DatasourceSpreadsheetGAS) Collect(out chan<- *TableData) error {
 	ch := make(chan *zip.File, 1000)
 	go func() {
 		for _, zipFile := range zipReader.File {
-			if strings.HasPrefix(zipFile.Name, "#") {
+			if strings.HasPrefix(path.Base(zipFile.Name), "#") {
 				continue
 			}
 			ch <- zipFile
@@ -46,40 +49,62 @@ func (d *DatasourceSpreadsheetGAS) Collect(out chan<- *TableData) error {
 		close(ch)
 	}()
 
-	var wg errgroup.Group
+	// the errors of every sheet are joined
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
 	for zipFile := range ch {
-		wg.Go(func() error {
-			file, err := zipFile.Open()
-			if err != nil {
-				return fmt.Errorf("failed to open the file: %s, %w", zipFile.Name, err)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.collectSheet(files, zipFile, out); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
 			}
-			defer file.Close()
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
 
-			rows, err := csv.NewReader(file).ReadAll()
-			if err != nil {
-				return fmt.Errorf("failed to read the file: %s, %w", zipFile.Name, err)
-			}
+// collectSheet - collects a csv file of the zip, which is a sheet
+func (d *DatasourceSpreadsheetGAS) collectSheet(files *FileSink, zipFile *zip.File, out chan<- *TableData) error {
+	file, err := zipFile.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open the file: %s, %w", zipFile.Name, err)
+	}
+	defer file.Close()
 
-			tableData, err := ParseTableData(zipFile.Name, rows)
-			if err != nil {
-				if errors.Is(err, ErrSkipTable) {
-					return nil
-				}
-				return err
-			}
-			if d.DebugSaveDir != nil {
-				if err := saveCSVFile(*d.DebugSaveDir, zipFile.Name, rows); err != nil {
-					return err
-				}
-			}
-			out <- tableData
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read the file: %s, %w", zipFile.Name, err)
+	}
+
+	// the entries are named <spreadsheet>/<sheet>.csv, or <sheet>.csv by the older scripts
+	spreadsheet, sheet := path.Split(zipFile.Name)
+	spreadsheet = strings.TrimSuffix(spreadsheet, "/")
+	sheet = strings.TrimSuffix(sheet, ".csv")
+
+	tableData, err := ParseTableData(spreadsheet, sheet, rows)
+	if err != nil {
+		if errors.Is(err, ErrSkipTable) {
 			return nil
-		})
+		}
+		return err
 	}
-	return wg.Wait()
+	if d.DebugSaveDir != nil {
+		if err := saveCSVFile(files, *d.DebugSaveDir, sheet, rows, d.DebugSaveDialect); err != nil {
+			return err
+		}
+	}
+	out <- tableData
+	return nil
 }
 
-func (d *DatasourceSpreadsheetGAS) callGASAndReadBase64() ([]byte, error) {
+func (d *DatasourceSpreadsheetGAS) callGASAndReadBase64(ctx context.Context) ([]byte, error) {
 	uri, err := url.Parse(d.URL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse url: %w", err)
@@ -91,7 +116,11 @@ func (d *DatasourceSpreadsheetGAS) callGASAndReadBase64() ([]byte, error) {
 	}
 	uri.RawQuery = queryValues.Encode()
 
-	res, err := http.Get(uri.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download zip: %w", err)
+	}
+	res, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download zip: %w", err)
 	}
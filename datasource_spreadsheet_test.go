@@ -0,0 +1,88 @@
+package nestcsv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDatasourceSpreadsheet(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+	})
+	mux.HandleFunc("GET /drive/v3/files", func(w http.ResponseWriter, r *http.Request) {
+		files := []map[string]string{{"id": "sub", "mimeType": googleFolderMimeType}}
+		if r.URL.Query().Get("q") == "'sub' in parents and trashed = false" {
+			files = []map[string]string{{"id": "items", "mimeType": googleSpreadsheetMimeType}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"files": files})
+	})
+	mux.HandleFunc("GET /v4/spreadsheets/items", func(w http.ResponseWriter, r *http.Request) {
+		dateFormat := `{"effectiveFormat":{"numberFormat":{"type":"DATE_TIME"}}}`
+		_, _ = w.Write([]byte(`{"properties":{"title":"Items"},"sheets":[` +
+			`{"properties":{"title":"items"},"data":[{"rowData":[{},{},{},{},{},{"values":[{},{},` + dateFormat + `]}]}]},` +
+			`{"properties":{"title":"empty"}},` +
+			`{"properties":{"title":"#memo"}}]}`))
+	})
+	mux.HandleFunc("GET /v4/spreadsheets/items/values:batchGet", func(w http.ResponseWriter, r *http.Request) {
+		if ranges := r.URL.Query()["ranges"]; len(ranges) != 2 || ranges[0] != "'items'" || ranges[1] != "'empty'" {
+			t.Errorf("unexpected ranges: %v", ranges)
+		}
+		if option := r.URL.Query().Get("dateTimeRenderOption"); option != "SERIAL_NUMBER" {
+			t.Errorf("unexpected dateTimeRenderOption: %s", option)
+		}
+		_, _ = w.Write([]byte(`{"valueRanges":[` +
+			`{"values":[[],["all","all","all"],["ID","Price","CreatedAt"],["int","float","time"],[],[1,1.5,45306.5],[2]]},` +
+			`{"values":[["constraint_row=true"],["all"],["ID"],["int"]]}]}`))
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token" && r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	datasource := &DatasourceSpreadsheet{
+		GoogleDriveFolderIDs: []string{"root"},
+		OAuth2: &SpreadsheetOAuth2{
+			ClientID:     "id",
+			ClientSecret: "secret",
+			RefreshToken: "refresh",
+		},
+		BaseURL: &server.URL,
+	}
+	out := make(chan *TableData, 10)
+	if err := datasource.Collect(context.Background(), nil, out); err != nil {
+		t.Fatal(err)
+	}
+	close(out)
+
+	var tables []*TableData
+	for td := range out {
+		tables = append(tables, td)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("expected 2 tables, got %d", len(tables))
+	}
+	td := tables[0]
+	if td.Name != "items" || td.File != "Items" || td.Sheet != "items" {
+		t.Errorf("unexpected table: %s, %s, %s", td.Name, td.File, td.Sheet)
+	}
+	if len(td.DataRows) != 2 || td.DataRows[0][1] != "1.5" || td.DataRows[1][1] != "" {
+		t.Errorf("unexpected rows: %v", td.DataRows)
+	}
+	// a date is formatted as the Apps Script datasource does
+	if got := td.DataRows[0][2]; got != "2024-01-15 12:00:00" {
+		t.Errorf("unexpected date: %s", got)
+	}
+	// the trimmed constraint row of a sheet without data is padded
+	if td := tables[1]; td.Name != "empty" || len(td.DataRows) != 0 || len(td.FieldConstraints) != 1 {
+		t.Errorf("unexpected table: %s, %v, %v", td.Name, td.DataRows, td.FieldConstraints)
+	}
+}
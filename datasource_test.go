@@ -0,0 +1,21 @@
+package nestcsv
+
+import "testing"
+
+func TestMatchFile(t *testing.T) {
+	patterns := []string{"./csv/*.csv", "sheets/*.xlsx"}
+	tests := map[string]bool{
+		"csv/items.csv":         true,
+		"./csv/items.csv":       true,
+		"csv/#items.csv":        false,
+		"sheets/items.xlsx":     true,
+		"sheets/~$items.xlsx":   false,
+		"sheets/items.xlsx.tmp": false,
+		"items.csv":             false,
+	}
+	for path, want := range tests {
+		if got := matchFile(patterns, path); got != want {
+			t.Errorf("matchFile(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package nestcsv
+
+import "context"
+
+// DatasourceTSV - reads the tab-separated files, which are parsed in the same way as the csv files
+type DatasourceTSV struct {
+	Patterns   []string `yaml:"patterns"`
+	CSVDialect `yaml:",inline"`
+}
+
+func (d *DatasourceTSV) Collect(ctx context.Context, files *FileSink, out chan<- *TableData) error {
+	return collectFiles(ctx, d.Patterns, func(path string) error {
+		return d.CollectFile(ctx, files, path, out)
+	})
+}
+
+func (d *DatasourceTSV) FilePatterns() []string {
+	return d.Patterns
+}
+
+func (d *DatasourceTSV) CollectFile(ctx context.Context, files *FileSink, path string, out chan<- *TableData) error {
+	return collectDelimitedFile(path, &d.CSVDialect, '\t', out)
+}
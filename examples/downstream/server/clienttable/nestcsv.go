@@ -2,6 +2,14 @@
 
 package clienttable
 
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+)
+
 const (
 	SampleDataName = "sample-data"
 )
@@ -18,12 +26,22 @@ func LoadTablesFromFile(basePath string) (*TableHolder, error) {
 	return &t, nil
 }
 
+func LoadTablesFromBinFile(basePath string) (*TableHolder, error) {
+	var t TableHolder
+	if err := t.SampleData.LoadBinFromFile(basePath); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
 type TableBase interface {
 	TableName() string
 	GetRows() interface{}
 	Load(data []byte) error
 	LoadFromString(jsonString string) error
 	LoadFromFile(basePath string) error
+	LoadBin(data []byte) error
+	LoadBinFromFile(basePath string) error
 }
 
 func (t *TableHolder) GetTables() []TableBase {
@@ -40,3 +58,158 @@ func (t *TableHolder) GetTable(tableName string) TableBase {
 		return nil
 	}
 }
+
+var (
+	errBinFormat    = errors.New("nestcsv: not a binary table")
+	errBinSchema    = errors.New("nestcsv: binary table schema mismatch, regenerate the code or the table")
+	errBinCorrupted = errors.New("nestcsv: corrupted binary table")
+)
+
+// binReader - reads the bin output, a read after an error returns the zero value
+type binReader struct {
+	data    []byte
+	strings []string
+	err     error
+}
+
+func newBinReader(data []byte, schema uint32) (*binReader, error) {
+	if len(data) < 9 || string(data[:4]) != "NCSV" || data[4] != 1 {
+		return nil, errBinFormat
+	}
+	if binary.LittleEndian.Uint32(data[5:9]) != schema {
+		return nil, errBinSchema
+	}
+	br := &binReader{data: data[9:]}
+	br.strings = make([]string, br.len())
+	for i := range br.strings {
+		n := br.len()
+		if br.err != nil {
+			return nil, br.err
+		}
+		br.strings[i] = string(br.data[:n])
+		br.data = br.data[n:]
+	}
+	return br, br.err
+}
+
+func (br *binReader) fail() {
+	if br.err == nil {
+		br.err = errBinCorrupted
+	}
+	br.data = nil
+}
+
+func (br *binReader) finish() error {
+	if br.err == nil && len(br.data) > 0 {
+		br.fail()
+	}
+	return br.err
+}
+
+func (br *binReader) uvarint() uint64 {
+	v, n := binary.Uvarint(br.data)
+	if n <= 0 {
+		br.fail()
+		return 0
+	}
+	br.data = br.data[n:]
+	return v
+}
+
+func (br *binReader) varint() int64 {
+	v, n := binary.Varint(br.data)
+	if n <= 0 {
+		br.fail()
+		return 0
+	}
+	br.data = br.data[n:]
+	return v
+}
+
+// len - reads a count, which can't be greater than the remaining bytes since every element takes a byte at least
+func (br *binReader) len() int {
+	n := br.uvarint()
+	if n > uint64(len(br.data)) {
+		br.fail()
+		return 0
+	}
+	return int(n)
+}
+
+func (br *binReader) float() float64 {
+	if len(br.data) < 8 {
+		br.fail()
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(br.data))
+	br.data = br.data[8:]
+	return v
+}
+
+func (br *binReader) bool() bool {
+	if len(br.data) < 1 {
+		br.fail()
+		return false
+	}
+	v := br.data[0] != 0
+	br.data = br.data[1:]
+	return v
+}
+
+func (br *binReader) string() string {
+	i := br.uvarint()
+	if i >= uint64(len(br.strings)) {
+		br.fail()
+		return ""
+	}
+	return br.strings[i]
+}
+
+func (br *binReader) time() time.Time {
+	return time.Unix(br.varint(), 0).UTC()
+}
+
+func (br *binReader) json() interface{} {
+	var v interface{}
+	if s := br.string(); s != "" {
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			br.fail()
+		}
+	}
+	return v
+}
+
+func readBinEnum[T any](br *binReader, values []T) T {
+	i := br.uvarint()
+	if i >= uint64(len(values)) {
+		br.fail()
+		var zero T
+		return zero
+	}
+	return values[i]
+}
+
+func readBinStruct[T any, PT interface {
+	*T
+	readBin(br *binReader)
+}](br *binReader) T {
+	var v T
+	PT(&v).readBin(br)
+	return v
+}
+
+func readBinList[T any](br *binReader, read func() T) []T {
+	list := make([]T, br.len())
+	for i := range list {
+		list[i] = read()
+	}
+	return list
+}
+
+func readBinNullable[T any](br *binReader, read func() T) *T {
+	if !br.bool() {
+		return nil
+	}
+	v := read()
+	return &v
+}
@@ -14,6 +14,14 @@ type SampleData struct {
 	CommonData string `json:"CommonData"`
 }
 
+func (r *SampleData) readBin(br *binReader) {
+	r.ID = int32(br.varint())
+	r.ServerData = br.string()
+	r.CommonData = br.string()
+}
+
+const SampleDataBinSchema uint32 = 0xf9105b99
+
 type SampleDataTable struct {
 	Rows []SampleData
 }
@@ -52,3 +60,28 @@ func (t *SampleDataTable) LoadFromFile(basePath string) error {
 
 	return json.NewDecoder(file).Decode(&t.Rows)
 }
+
+func (t *SampleDataTable) LoadBin(data []byte) error {
+	br, err := newBinReader(data, SampleDataBinSchema)
+	if err != nil {
+		return err
+	}
+	count := br.len()
+	rows := make([]SampleData, count)
+	for i := range rows {
+		rows[i].readBin(br)
+	}
+	if err := br.finish(); err != nil {
+		return err
+	}
+	t.Rows = rows
+	return nil
+}
+
+func (t *SampleDataTable) LoadBinFromFile(basePath string) error {
+	data, err := os.ReadFile(filepath.Join(basePath, "sample-data.bin"))
+	if err != nil {
+		return err
+	}
+	return t.LoadBin(data)
+}
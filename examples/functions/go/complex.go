@@ -12,13 +12,32 @@ type ComplexA struct {
 	SKU2 SKU `json:"SKU2"`
 }
 
+func (r *ComplexA) readBin(br *binReader) {
+	r.SKU2 = readBinStruct[SKU](br)
+}
+
 type Complex struct {
 	ID      int32    `json:"ID"`
 	SKU     []SKU    `json:"SKU"`
 	Rewards []Reward `json:"Rewards"`
 	A       ComplexA `json:"A"`
+	TypesID int32    `json:"TypesID"`
+}
+
+func (r *Complex) TypesIDRef(tables *TableHolder) (*Types, bool) {
+	return tables.Types.Find(r.TypesID)
+}
+
+func (r *Complex) readBin(br *binReader) {
+	r.ID = int32(br.varint())
+	r.SKU = readBinList(br, func() SKU { return readBinStruct[SKU](br) })
+	r.Rewards = readBinList(br, func() Reward { return readBinStruct[Reward](br) })
+	r.A = readBinStruct[ComplexA](br)
+	r.TypesID = int32(br.varint())
 }
 
+const ComplexBinSchema uint32 = 0xc9237080
+
 type ComplexTable struct {
 	Rows []Complex
 }
@@ -57,3 +76,28 @@ func (t *ComplexTable) LoadFromFile(basePath string) error {
 
 	return json.NewDecoder(file).Decode(&t.Rows)
 }
+
+func (t *ComplexTable) LoadBin(data []byte) error {
+	br, err := newBinReader(data, ComplexBinSchema)
+	if err != nil {
+		return err
+	}
+	count := br.len()
+	rows := make([]Complex, count)
+	for i := range rows {
+		rows[i].readBin(br)
+	}
+	if err := br.finish(); err != nil {
+		return err
+	}
+	t.Rows = rows
+	return nil
+}
+
+func (t *ComplexTable) LoadBinFromFile(basePath string) error {
+	data, err := os.ReadFile(filepath.Join(basePath, "complex.bin"))
+	if err != nil {
+		return err
+	}
+	return t.LoadBin(data)
+}
@@ -8,8 +8,20 @@ type RewardParamValue struct {
 	Float float64 `json:"Float"`
 }
 
+func (r *RewardParamValue) readBin(br *binReader) {
+	r.Str = br.string()
+	r.Int = int32(br.varint())
+	r.Float = br.float()
+}
+
 type Reward struct {
-	Type       string           `json:"Type"`
+	Type       RewardType       `json:"Type"`
 	ParamValue RewardParamValue `json:"ParamValue"`
 	ParamType  string           `json:"ParamType"`
 }
+
+func (r *Reward) readBin(br *binReader) {
+	r.Type = readBinEnum(br, RewardTypeValues)
+	r.ParamValue = readBinStruct[RewardParamValue](br)
+	r.ParamType = br.string()
+}
@@ -6,3 +6,8 @@ type SKU struct {
 	Type string `json:"Type"`
 	ID   string `json:"ID"`
 }
+
+func (r *SKU) readBin(br *binReader) {
+	r.Type = br.string()
+	r.ID = br.string()
+}
@@ -22,8 +22,28 @@ type Types struct {
 	FloatArray  []float64   `json:"FloatArray"`
 	StringArray []string    `json:"StringArray"`
 	TimeArray   []time.Time `json:"TimeArray"`
+	OptionalInt *int32      `json:"OptionalInt"`
+	Grade       *Grade      `json:"Grade"`
 }
 
+func (r *Types) readBin(br *binReader) {
+	r.Int = int32(br.varint())
+	r.Long = br.varint()
+	r.Float = br.float()
+	r.String = br.string()
+	r.Time = br.time()
+	r.Json = br.json()
+	r.IntArray = readBinList(br, func() int32 { return int32(br.varint()) })
+	r.LongArray = readBinList(br, func() int64 { return br.varint() })
+	r.FloatArray = readBinList(br, func() float64 { return br.float() })
+	r.StringArray = readBinList(br, func() string { return br.string() })
+	r.TimeArray = readBinList(br, func() time.Time { return br.time() })
+	r.OptionalInt = readBinNullable(br, func() int32 { return int32(br.varint()) })
+	r.Grade = readBinNullable(br, func() Grade { return readBinEnum(br, GradeValues) })
+}
+
+const TypesBinSchema uint32 = 0xb5e08c99
+
 type TypesTable struct {
 	Rows map[string]Types
 }
@@ -60,3 +80,29 @@ func (t *TypesTable) LoadFromFile(basePath string) error {
 
 	return json.NewDecoder(file).Decode(&t.Rows)
 }
+
+func (t *TypesTable) LoadBin(data []byte) error {
+	br, err := newBinReader(data, TypesBinSchema)
+	if err != nil {
+		return err
+	}
+	count := br.len()
+	rows := make(map[string]Types, count)
+	for i := 0; i < count; i++ {
+		key := br.string()
+		rows[key] = readBinStruct[Types](br)
+	}
+	if err := br.finish(); err != nil {
+		return err
+	}
+	t.Rows = rows
+	return nil
+}
+
+func (t *TypesTable) LoadBinFromFile(basePath string) error {
+	data, err := os.ReadFile(filepath.Join(basePath, "types.bin"))
+	if err != nil {
+		return err
+	}
+	return t.LoadBin(data)
+}
@@ -52,6 +52,26 @@ struct FNestComplex : public FNestTableDataBase
         return true;
     }
 
+    virtual void ReadBin(FNestBinReader& Reader) override
+    {
+        {
+            const int32 Count = Reader.ReadLength();
+            Tags.Empty(Count);
+            for (int32 Index = 0; Index < Count && Reader.IsValid(); ++Index)
+            {
+                Tags.Add(Reader.ReadString());
+            }
+        }
+        {
+            const int32 Count = Reader.ReadLength();
+            SKU.Empty(Count);
+            for (int32 Index = 0; Index < Count && Reader.IsValid(); ++Index)
+            {
+                SKU.AddDefaulted_GetRef().ReadBin(Reader);
+            }
+        }
+    }
+
     //NESTCSV:NESTCOMPLEX_EXTRA_BODY_START
     void CustomFunction()
     {
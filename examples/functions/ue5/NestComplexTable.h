@@ -44,6 +44,22 @@ struct FNestComplexTable : public FNestTableBase
         return true;
     }
 
+    virtual bool LoadBin(const TArray<uint8>& Bytes) override
+    {
+        FNestBinReader Reader(Bytes, 0xFCB021E3u);
+        const int32 Count = Reader.ReadLength();
+        TArray<FNestComplex> _Result;
+        _Result.Reserve(Count);
+        for (int32 Index = 0; Index < Count && Reader.IsValid(); ++Index)
+        {
+            _Result.AddDefaulted_GetRef().ReadBin(Reader);
+        }
+        if (!Reader.IsFinished()) return false;
+
+        Rows = MoveTemp(_Result);
+        return true;
+    }
+
     //NESTCSV:NESTCOMPLEX_EXTRA_BODY_START
     void CustomFunction()
     {
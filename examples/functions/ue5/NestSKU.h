@@ -31,6 +31,12 @@ struct FNestSKU : public FNestTableDataBase
         return true;
     }
 
+    virtual void ReadBin(FNestBinReader& Reader) override
+    {
+        Type = Reader.ReadString();
+        ID = Reader.ReadString();
+    }
+
     //NESTCSV:NESTSKU_EXTRA_BODY_START
     
     //NESTCSV:NESTSKU_EXTRA_BODY_END
@@ -3,6 +3,7 @@
 #pragma once
 
 #include "Json.h"
+#include "NestBinReader.h"
 #include "NestTableBase.generated.h"
 
 USTRUCT(BlueprintType)
@@ -25,4 +26,5 @@ struct FNestTableBase
         }
         return false;
     }
+    virtual bool LoadBin(const TArray<uint8>& Bytes) { return false; }
 };
@@ -3,6 +3,7 @@
 #pragma once
 
 #include "Json.h"
+#include "NestBinReader.h"
 #include "NestTableDataBase.generated.h"
 
 USTRUCT(BlueprintType)
@@ -24,4 +25,5 @@ struct FNestTableDataBase
         }
         return false;
     }
+    virtual void ReadBin(FNestBinReader& Reader) {}
 };
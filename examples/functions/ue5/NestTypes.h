@@ -3,6 +3,7 @@
 #pragma once
 
 #include "NestTableDataBase.h"
+#include "NestGrade.h"
 
 //NESTCSV:NESTTYPES_EXTRA_INCLUDE_START
 
@@ -36,6 +37,8 @@ struct FNestTypes : public FNestTableDataBase
     TArray<FString> StringArray;
     UPROPERTY(VisibleAnywhere, BlueprintReadOnly)
     TArray<FDateTime> TimeArray;
+    TOptional<int32> OptionalInt;
+    TOptional<ENestGrade> Grade;
 
     virtual bool Load(const TSharedPtr<FJsonObject>& JsonObject) override
     {
@@ -104,11 +107,85 @@ struct FNestTypes : public FNestTableDataBase
                 _Result.TimeArray.Add(DateTime);
             }
         }
+        {
+            const TSharedPtr<FJsonValue> Item = JsonObject->TryGetField(TEXT("OptionalInt"));
+            if (Item.IsValid() && !Item->IsNull())
+            {
+                int32 FieldItem;
+                if (!Item->TryGetNumber(FieldItem)) return false;
+                _Result.OptionalInt = FieldItem;
+            }
+        }
+        {
+            const TSharedPtr<FJsonValue> Item = JsonObject->TryGetField(TEXT("Grade"));
+            if (Item.IsValid() && !Item->IsNull())
+            {
+                ENestGrade FieldItem;
+                FString EnumStr;
+                if (!Item->TryGetString(EnumStr)) return false;
+                if (!LexTryParseString(FieldItem, *EnumStr)) return false;
+                _Result.Grade = FieldItem;
+            }
+        }
 
         *this = MoveTemp(_Result);
         return true;
     }
 
+    virtual void ReadBin(FNestBinReader& Reader) override
+    {
+        Int = static_cast<int32>(Reader.ReadVarint());
+        Long = Reader.ReadVarint();
+        Float = Reader.ReadFloat();
+        String = Reader.ReadString();
+        Time = Reader.ReadTime();
+        Json = Reader.ReadJson();
+        {
+            const int32 Count = Reader.ReadLength();
+            IntArray.Empty(Count);
+            for (int32 Index = 0; Index < Count && Reader.IsValid(); ++Index)
+            {
+                IntArray.Add(static_cast<int32>(Reader.ReadVarint()));
+            }
+        }
+        {
+            const int32 Count = Reader.ReadLength();
+            LongArray.Empty(Count);
+            for (int32 Index = 0; Index < Count && Reader.IsValid(); ++Index)
+            {
+                LongArray.Add(Reader.ReadVarint());
+            }
+        }
+        {
+            const int32 Count = Reader.ReadLength();
+            FloatArray.Empty(Count);
+            for (int32 Index = 0; Index < Count && Reader.IsValid(); ++Index)
+            {
+                FloatArray.Add(Reader.ReadFloat());
+            }
+        }
+        {
+            const int32 Count = Reader.ReadLength();
+            StringArray.Empty(Count);
+            for (int32 Index = 0; Index < Count && Reader.IsValid(); ++Index)
+            {
+                StringArray.Add(Reader.ReadString());
+            }
+        }
+        {
+            const int32 Count = Reader.ReadLength();
+            TimeArray.Empty(Count);
+            for (int32 Index = 0; Index < Count && Reader.IsValid(); ++Index)
+            {
+                TimeArray.Add(Reader.ReadTime());
+            }
+        }
+        if (Reader.ReadBool()) OptionalInt = static_cast<int32>(Reader.ReadVarint());
+        else OptionalInt.Reset();
+        if (Reader.ReadBool()) Grade = static_cast<ENestGrade>(Reader.ReadEnum(3));
+        else Grade.Reset();
+    }
+
     //NESTCSV:NESTTYPES_EXTRA_BODY_START
     
     //NESTCSV:NESTTYPES_EXTRA_BODY_END
@@ -44,6 +44,23 @@ struct FNestTypesTable : public FNestTableBase
         return true;
     }
 
+    virtual bool LoadBin(const TArray<uint8>& Bytes) override
+    {
+        FNestBinReader Reader(Bytes, 0xB5E08C99u);
+        const int32 Count = Reader.ReadLength();
+        TMap<FString, FNestTypes> _Result;
+        _Result.Reserve(Count);
+        for (int32 Index = 0; Index < Count && Reader.IsValid(); ++Index)
+        {
+            const FString Key = Reader.ReadString();
+            _Result.Add(Key).ReadBin(Reader);
+        }
+        if (!Reader.IsFinished()) return false;
+
+        Rows = MoveTemp(_Result);
+        return true;
+    }
+
     const FNestTypes* Find(int32 ID) const
     {
         return Rows.Find(FString::FromInt(ID));
@@ -2,6 +2,7 @@
 
 using System;
 using System.Collections.Generic;
+using System.IO;
 using Newtonsoft.Json;
 using UnityEngine;
 
@@ -15,6 +16,12 @@ public partial class ComplexData : TableDataBase
     public List<string> Tags;
     [JsonProperty("SKU")]
     public List<SKUData> SKU;
+
+    public override void ReadBin(BinReader reader)
+    {
+        Tags = reader.ReadList(() => reader.ReadString());
+        SKU = reader.ReadList(() => reader.ReadStruct<SKUData>());
+    }
 }
 
 public partial class ComplexDB : TableBase
@@ -33,6 +40,27 @@ public partial class ComplexDB : TableBase
         return true;
     }
 
+    public override bool LoadBin(byte[] bytes)
+    {
+        try
+        {
+            var reader = new BinReader(bytes, 0xFCB021E3u);
+            var count = reader.ReadLength();
+            var result = new List<ComplexData>(count);
+            for (var i = 0; i < count; i++)
+            {
+                result.Add(reader.ReadStruct<ComplexData>());
+            }
+            reader.Finish();
+            Rows = result;
+            return true;
+        }
+        catch (InvalidDataException)
+        {
+            return false;
+        }
+    }
+
     private static ComplexDB s_instance;
 
     public static ComplexDB inst()
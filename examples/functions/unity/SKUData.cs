@@ -15,5 +15,11 @@ public partial class SKUData : TableDataBase
     public string Type;
     [JsonProperty("ID")]
     public string ID;
+
+    public override void ReadBin(BinReader reader)
+    {
+        Type = reader.ReadString();
+        ID = reader.ReadString();
+    }
 }
 }
\ No newline at end of file
@@ -26,6 +26,18 @@ public abstract class TableBase
         return Load(File.ReadAllText(filePath));
     }
 
+    public abstract bool LoadBin(byte[] bytes);
+
+    public virtual bool LoadBinFromFile(string basePath, string fileSuffix = ".bin")
+    {
+        var filePath = Path.Combine(basePath, TableName + fileSuffix);
+        if (!File.Exists(filePath))
+        {
+            return false;
+        }
+        return LoadBin(File.ReadAllBytes(filePath));
+    }
+
     public virtual bool LoadFromResources(string resourceFolder)
     {
         var resourcePath = string.IsNullOrEmpty(resourceFolder) ? TableName : resourceFolder + "/" + TableName;
@@ -37,5 +49,18 @@ public abstract class TableBase
         }
         return Load(textAsset.text);
     }
+
+    // LoadBinFromResources - the bin output must be written with the ".bytes" file suffix to be a TextAsset
+    public virtual bool LoadBinFromResources(string resourceFolder)
+    {
+        var resourcePath = string.IsNullOrEmpty(resourceFolder) ? TableName : resourceFolder + "/" + TableName;
+        var textAsset = Resources.Load<TextAsset>(resourcePath);
+        if (textAsset == null)
+        {
+            Debug.LogError("[" + GetType().Name + "] " + TableName + ".bytes not found in Resources/" + resourcePath);
+            return false;
+        }
+        return LoadBin(textAsset.bytes);
+    }
 }
 }
@@ -7,5 +7,6 @@ namespace Nestcsv.Example
 [Serializable]
 public abstract class TableDataBase
 {
+    public abstract void ReadBin(BinReader reader);
 }
 }
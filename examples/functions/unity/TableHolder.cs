@@ -52,6 +52,15 @@ public partial class TableHolder
         return holder;
     }
 
+    public void LoadBinFromFile(string basePath, string fileSuffix = ".bin")
+    {
+        foreach (var table in GetTables())
+        {
+            table.LoadBinFromFile(basePath, fileSuffix);
+        }
+        Instance = this;
+    }
+
     public bool LoadFromJsonMap(IReadOnlyDictionary<string, string> jsonBySheet)
     {
         var success = true;
@@ -80,6 +89,20 @@ public partial class TableHolder
         return success;
     }
 
+    public bool LoadFromBinMap(IReadOnlyDictionary<string, byte[]> binBySheet)
+    {
+        var success = true;
+        foreach (var kv in binBySheet)
+        {
+            var table = GetTable(kv.Key);
+            if (table == null || !table.LoadBin(kv.Value))
+            {
+                success = false;
+            }
+        }
+        return success;
+    }
+
     public void LoadFromResources(string resourceFolder = "MetaData")
     {
         foreach (var table in GetTables())
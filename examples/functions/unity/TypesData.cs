@@ -2,6 +2,8 @@
 
 using System;
 using System.Collections.Generic;
+using System.Globalization;
+using System.IO;
 using Newtonsoft.Json;
 using Newtonsoft.Json.Linq;
 using UnityEngine;
@@ -34,6 +36,27 @@ public partial class TypesData : TableDataBase
     public List<string> StringArray;
     [JsonProperty("TimeArray")]
     public List<DateTime> TimeArray;
+    [JsonProperty("OptionalInt")]
+    public int? OptionalInt;
+    [JsonProperty("Grade")]
+    public Grade? Grade;
+
+    public override void ReadBin(BinReader reader)
+    {
+        Int = (int)reader.ReadVarint();
+        Long = reader.ReadVarint();
+        Float = reader.ReadFloat();
+        String = reader.ReadString();
+        Time = reader.ReadTime();
+        Json = reader.ReadJson();
+        IntArray = reader.ReadList(() => (int)reader.ReadVarint());
+        LongArray = reader.ReadList(() => reader.ReadVarint());
+        FloatArray = reader.ReadList(() => reader.ReadFloat());
+        StringArray = reader.ReadList(() => reader.ReadString());
+        TimeArray = reader.ReadList(() => reader.ReadTime());
+        OptionalInt = reader.ReadBool() ? (int)reader.ReadVarint() : (int?)null;
+        Grade = reader.ReadBool() ? (Grade)reader.ReadEnum(3) : (Grade?)null;
+    }
 }
 
 public partial class TypesDB : TableBase
@@ -52,6 +75,28 @@ public partial class TypesDB : TableBase
         return true;
     }
 
+    public override bool LoadBin(byte[] bytes)
+    {
+        try
+        {
+            var reader = new BinReader(bytes, 0xB5E08C99u);
+            var count = reader.ReadLength();
+            var result = new Dictionary<int, TypesData>(count);
+            for (var i = 0; i < count; i++)
+            {
+                var key = int.Parse(reader.ReadString(), CultureInfo.InvariantCulture);
+                result[key] = reader.ReadStruct<TypesData>();
+            }
+            reader.Finish();
+            Rows = result;
+            return true;
+        }
+        catch (InvalidDataException)
+        {
+            return false;
+        }
+    }
+
     public TypesData Find(int id)
     {
         return Rows.TryGetValue(id, out var row) ? row : null;
@@ -0,0 +1,280 @@
+package nestcsv
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// manifestFileName - the manifest written into the root directory of every output and codegen
+const manifestFileName = "nestcsv-manifest.json"
+
+// FileSink - where a run creates the files of its outputs and codegens, on disk, or in memory for Run and Check
+//
+//	It records the files created in each root directory, for the manifests. A nil FileSink writes on disk
+//	and records nothing.
+type FileSink struct {
+	capture bool
+
+	mu      sync.Mutex
+	data    map[string][]byte              // the captured files
+	dirs    map[string]map[string]struct{} // the files created in each root directory, relative to it
+	partial map[string]bool                // the root directories where an unchanged file is not written again
+}
+
+// GeneratedFile - a file created by a FileSink
+type GeneratedFile interface {
+	io.WriteCloser
+	Name() string
+}
+
+// NewFileSink - a sink writing on disk, or capturing the files into memory if capture is set
+func NewFileSink(capture bool) *FileSink {
+	return &FileSink{
+		capture: capture,
+		data:    make(map[string][]byte),
+		dirs:    make(map[string]map[string]struct{}),
+		partial: make(map[string]bool),
+	}
+}
+
+// Create - creates the file in the root directory, which is listed in the manifest of the directory
+func (f *FileSink) Create(rootDir, fileName, ext string) (GeneratedFile, error) {
+	filePath := makeFilePath(rootDir, fileName, ext)
+	f.add(rootDir, filePath)
+	if f != nil && f.capture {
+		return &capturedFile{files: f, path: filePath}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create the directory: %s, %w", filepath.Dir(filePath), err)
+	}
+	file, err := os.Create(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the file: %s, %w", filePath, err)
+	}
+	return file, nil
+}
+
+// ReadFile - reads a file written by the previous build, or by this one if it's captured
+func (f *FileSink) ReadFile(path string) ([]byte, error) {
+	if f != nil && f.capture {
+		if data, ok := f.get(path); ok {
+			return data, nil
+		}
+	}
+	return os.ReadFile(path)
+}
+
+// WriteFile - writes a whole file, which is not listed in a manifest
+func (f *FileSink) WriteFile(path string, data []byte) error {
+	if f != nil && f.capture {
+		f.put(path, data)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create the directory: %s, %w", filepath.Dir(path), err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// add - records a file created in the root directory
+func (f *FileSink) add(rootDir, path string) {
+	if f == nil {
+		return
+	}
+	rootDir = filepath.Clean(rootDir)
+	rel, err := filepath.Rel(rootDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.dirs[rootDir] == nil {
+		f.dirs[rootDir] = make(map[string]struct{})
+	}
+	f.dirs[rootDir][filepath.ToSlash(rel)] = struct{}{}
+}
+
+// skip - an output or a codegen skipped writing an unchanged file, so the root directory keeps the files of the manifest
+func (f *FileSink) skip(target any) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.partial[filepath.Clean(targetRootDir(target))] = true
+}
+
+func (f *FileSink) get(path string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.data[filepath.Clean(path)]
+	return data, ok
+}
+
+func (f *FileSink) put(path string, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[filepath.Clean(path)] = bytes.Clone(data)
+}
+
+// capturedFile - a file created by a capturing sink, captured when it's closed
+type capturedFile struct {
+	bytes.Buffer
+	files *FileSink
+	path  string
+}
+
+func (f *capturedFile) Name() string {
+	return f.path
+}
+
+func (f *capturedFile) Close() error {
+	f.files.put(f.path, f.Bytes())
+	return nil
+}
+
+// Manifest - the files generated into the root directory of an output or a codegen
+type Manifest struct {
+	Files []ManifestFile `json:"files"`
+}
+
+type ManifestFile struct {
+	Path   string `json:"path"` // relative to the root directory, with the forward slashes
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// writeManifests - writes the manifest of every root directory, and removes the files of the previous manifest
+// which are not generated again, unless noPrune is set. The removed files are returned, the check mode only
+// returns them.
+//
+//	A root directory where a file is skipped by the build cache or the watcher keeps the files of the previous
+//	manifest, as they are not known to be stale.
+func (f *FileSink) writeManifests(config *Config, noPrune bool) ([]string, error) {
+	var rootDirs []string
+	for _, output := range config.Outputs {
+		rootDirs = appendUnique(rootDirs, filepath.Clean(targetRootDir(output.loaded)))
+	}
+	for _, codegen := range config.Codegens {
+		rootDirs = appendUnique(rootDirs, filepath.Clean(targetRootDir(codegen.loaded)))
+	}
+
+	var removed []string
+	for _, rootDir := range rootDirs {
+		manifestPath := filepath.Join(rootDir, manifestFileName)
+		prev, err := readManifest(manifestPath)
+		if err != nil {
+			return removed, err
+		}
+		files := maps.Clone(f.dirs[rootDir])
+		if files == nil {
+			files = make(map[string]struct{})
+		}
+		if len(files) == 0 && prev == nil {
+			continue
+		}
+
+		var manifest Manifest
+		for _, prevFile := range prev.files() {
+			path, ok := manifestFilePath(rootDir, prevFile.Path)
+			if _, generated := files[prevFile.Path]; generated || !ok {
+				continue
+			}
+			if f.partial[rootDir] || noPrune {
+				files[prevFile.Path] = struct{}{}
+				continue
+			}
+			if !f.capture {
+				if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return removed, fmt.Errorf("failed to remove the stale file: %s, %w", path, err)
+				}
+			}
+			removed = append(removed, path)
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(files)) {
+			data, err := f.ReadFile(filepath.Join(rootDir, filepath.FromSlash(name)))
+			if errors.Is(err, os.ErrNotExist) {
+				// a kept file removed by hand
+				continue
+			}
+			if err != nil {
+				return removed, err
+			}
+			sum := sha256.Sum256(data)
+			manifest.Files = append(manifest.Files, ManifestFile{
+				Path:   name,
+				Size:   int64(len(data)),
+				SHA256: hex.EncodeToString(sum[:]),
+			})
+		}
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return removed, err
+		}
+		if err := f.WriteFile(manifestPath, append(data, '\n')); err != nil {
+			return removed, fmt.Errorf("failed to write the manifest: %s, %w", manifestPath, err)
+		}
+	}
+	return removed, nil
+}
+
+// readManifest - nil if the manifest doesn't exist
+func readManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the manifest: %s, %w", path, err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest: %s, %w", path, err)
+	}
+	return &manifest, nil
+}
+
+func (m *Manifest) files() []ManifestFile {
+	if m == nil {
+		return nil
+	}
+	return m.Files
+}
+
+// manifestFilePath - a path of the manifest out of the root directory is never removed
+func manifestFilePath(rootDir, name string) (string, bool) {
+	if !filepath.IsLocal(filepath.FromSlash(name)) || name == manifestFileName {
+		return "", false
+	}
+	return filepath.Join(rootDir, filepath.FromSlash(name)), true
+}
+
+// targetRootDir - the root directory of an output or a codegen
+func targetRootDir(target any) string {
+	v := reflect.ValueOf(target)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		if field := v.FieldByName("RootDir"); field.IsValid() && field.Kind() == reflect.String {
+			return cmp.Or(field.String(), ".")
+		}
+	}
+	return "."
+}
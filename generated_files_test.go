@@ -0,0 +1,74 @@
+package nestcsv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifestFilePath(t *testing.T) {
+	tests := map[string]bool{
+		"items.json":          true,
+		"client/items.json":   true,
+		"../items.json":       false,
+		"/etc/passwd":         false,
+		"client/../../a.json": false,
+		manifestFileName:      false,
+	}
+	for name, want := range tests {
+		path, ok := manifestFilePath("json", name)
+		if ok != want {
+			t.Errorf("manifestFilePath(%s) = %v, want %v", name, ok, want)
+		}
+		if ok && path != filepath.Join("json", filepath.FromSlash(name)) {
+			t.Errorf("manifestFilePath(%s) = %s", name, path)
+		}
+	}
+}
+
+func TestGeneratePrune(t *testing.T) {
+	rootDir := t.TempDir()
+	newConfig := func(noPrune bool, files ...string) *Config {
+		tables := make([]MemoryTable, 0, len(files))
+		for _, file := range files {
+			tables = append(tables, MemoryTable{File: file, Rows: [][]string{{""}, {"all"}, {"ID"}, {"int"}, {""}, {"1"}}})
+		}
+		return &Config{
+			Datasources: []DatasourceConfig{NewDatasourceConfig(&DatasourceMemory{Tables: tables})},
+			Outputs:     []OutputConfig{NewOutputConfig(&TableWriterJSON{RootDir: rootDir}, "all")},
+			NoPrune:     noPrune,
+		}
+	}
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(rootDir, name))
+		return err == nil
+	}
+
+	if err := Generate(newConfig(false, "items.csv", "shops.csv")); err != nil {
+		t.Fatal(err)
+	}
+	if !exists("items.json") || !exists("shops.json") {
+		t.Fatal("the tables are not generated")
+	}
+
+	// -no-prune keeps the file of the removed table
+	if err := Generate(newConfig(true, "items.csv")); err != nil {
+		t.Fatal(err)
+	}
+	if !exists("shops.json") {
+		t.Error("shops.json is removed with no_prune")
+	}
+
+	if err := Generate(newConfig(false, "items.csv", "shops.csv")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Generate(newConfig(false, "items.csv")); err != nil {
+		t.Fatal(err)
+	}
+	if exists("shops.json") {
+		t.Error("shops.json of the removed table is not pruned")
+	}
+	if !exists("items.json") {
+		t.Error("items.json is removed")
+	}
+}
@@ -0,0 +1,91 @@
+package nestcsv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Plugin - an external executable, which reads a JSON request from stdin and writes a JSON response to stdout
+//
+//	Every request has the "kind" of the plugin, "datasource", "output" or "codegen", and the "options" of the config.
+//	A plugin fails by exiting with a non-zero code, its stderr is reported in the error.
+type Plugin struct {
+	Command string            `yaml:"command"`
+	Args    []string          `yaml:"args"`
+	Env     map[string]string `yaml:"env"`     // appended to the environment of nestcsv
+	Options map[string]any    `yaml:"options"` // passed in every request as is
+}
+
+// PluginFile - a file written by nestcsv for an output or a codegen plugin, into its root directory
+type PluginFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content,omitempty"`
+	Data    []byte `json:"data,omitempty"` // the base64 encoded content of a binary file
+}
+
+// call - runs the plugin with the request of the kind, and decodes its response
+func (p *Plugin) call(ctx context.Context, kind string, request map[string]any, response any) error {
+	if p.Command == "" {
+		return errors.New("plugin requires command")
+	}
+	request = extendMap(map[string]any{
+		"kind":    kind,
+		"options": p.Options,
+	}, request)
+	input, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode the plugin request: %s, %w", p.Command, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Command, p.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = os.Environ()
+	for key, value := range p.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("plugin failed: %s, %w: %s", p.Command, err, msg)
+		}
+		return fmt.Errorf("plugin failed: %s, %w", p.Command, err)
+	}
+	if err := json.Unmarshal(stdout.Bytes(), response); err != nil {
+		return fmt.Errorf("failed to decode the plugin response: %s, %w", p.Command, err)
+	}
+	return nil
+}
+
+// writePluginFiles - writes the files of a plugin response, a path out of the root directory is rejected
+func writePluginFiles(files *FileSink, rootDir string, pluginFiles []PluginFile) error {
+	for _, f := range pluginFiles {
+		if !filepath.IsLocal(filepath.FromSlash(f.Path)) {
+			return fmt.Errorf("invalid plugin file path: %s", f.Path)
+		}
+		file, err := files.Create(rootDir, filepath.FromSlash(f.Path), "")
+		if err != nil {
+			return err
+		}
+		data := f.Data
+		if data == nil {
+			data = []byte(f.Content)
+		}
+		_, err = file.Write(data)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write the file: %s, %w", f.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package nestcsv
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewPluginCode(t *testing.T) {
+	items := &CodeFile{Name: "items", IsTable: true}
+	shops := &CodeFile{Name: "shops", IsTable: true}
+	items.Struct = &CodeStruct{Name: "Item", Fields: []*CodeStructField{
+		{Name: "ID", Type: FieldTypeInt},
+		{Name: "ShopID", Type: FieldTypeInt, TableRef: shops},
+	}}
+	items.IDField = items.Struct.Fields[0]
+	items.FileRefs = []*CodeFile{shops}
+	shops.Struct = &CodeStruct{Name: "Shop", Fields: []*CodeStructField{
+		{Name: "ItemID", Type: FieldTypeInt, TableRef: items},
+	}}
+	shops.FileRefs = []*CodeFile{items}
+
+	data, err := json.Marshal(newPluginCode(&Code{Tables: []*CodeFile{items, shops}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"table_ref":"shops"`, `"file_refs":["items"]`, `"id_field":"ID"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("missing %s in %s", want, data)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package nestcsv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"slices"
+	"sync"
+)
+
+const defaultProtobufLockFile = "protobuf.lock.yaml"
+
+// protobufLockMutex - the protobuf output writes the tables concurrently, and the codegen follows it
+var protobufLockMutex sync.Mutex
+
+// protobufLock - the field numbers of the messages and the enums, kept across the builds
+//
+//	A number is assigned once and never reused, so that reordering or removing the columns
+//	keeps the wire compatibility. The numbers of the removed fields are emitted as reserved.
+type protobufLock struct {
+	Messages map[string]map[string]int `yaml:"messages"`
+	Enums    map[string]map[string]int `yaml:"enums"`
+}
+
+// withProtobufLock - loads the lock file, and saves it after fn if a number is assigned
+func withProtobufLock(files *FileSink, path string, fn func(lock *protobufLock) error) error {
+	protobufLockMutex.Lock()
+	defer protobufLockMutex.Unlock()
+
+	lock := &protobufLock{}
+	data, err := files.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read the protobuf lock file: %s, %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return fmt.Errorf("failed to parse the protobuf lock file: %s, %w", path, err)
+	}
+	if lock.Messages == nil {
+		lock.Messages = make(map[string]map[string]int)
+	}
+	if lock.Enums == nil {
+		lock.Enums = make(map[string]map[string]int)
+	}
+
+	if err := fn(lock); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Code generated by \"nestcsv\"; DO NOT EDIT, commit it to keep the protobuf field numbers stable.\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(lock); err != nil {
+		return err
+	}
+	if bytes.Equal(buf.Bytes(), data) {
+		return nil
+	}
+	return files.WriteFile(path, buf.Bytes())
+}
+
+// assignFile - assigns the numbers of every message and enum declared in or referred by a file
+func (l *protobufLock) assignFile(file *CodeFile) {
+	if file.Enum != nil {
+		l.assignEnum(file.Enum)
+	}
+	if file.Struct != nil {
+		l.assignStruct(file.Struct)
+	}
+}
+
+func (l *protobufLock) assignStruct(s *CodeStruct) {
+	for _, field := range s.Fields {
+		l.fieldNumber(s, field)
+		if field.StructRef != nil {
+			l.assignStruct(field.StructRef)
+		}
+		if field.EnumRef != nil {
+			l.assignEnum(field.EnumRef)
+		}
+	}
+}
+
+func (l *protobufLock) assignEnum(e *CodeEnum) {
+	for _, value := range e.Values {
+		l.enumNumber(e, value)
+	}
+}
+
+// fieldNumber - returns the number of a field, a new field takes the next number of the message
+func (l *protobufLock) fieldNumber(s *CodeStruct, field *CodeStructField) int {
+	fields := l.Messages[protobufTypeName(s.Name)]
+	if fields == nil {
+		fields = make(map[string]int)
+		l.Messages[protobufTypeName(s.Name)] = fields
+	}
+	if n, ok := fields[field.Name]; ok {
+		return n
+	}
+	n := 1
+	for _, used := range fields {
+		n = max(n, used+1)
+	}
+	if n >= 19000 && n <= 19999 {
+		// reserved for the protobuf implementation
+		n = 20000
+	}
+	fields[field.Name] = n
+	return n
+}
+
+// enumNumber - returns the number of an enum value, the first value takes 0 as proto3 requires
+func (l *protobufLock) enumNumber(e *CodeEnum, value string) int {
+	values := l.Enums[protobufTypeName(e.Name)]
+	if values == nil {
+		values = make(map[string]int)
+		l.Enums[protobufTypeName(e.Name)] = values
+	}
+	if n, ok := values[value]; ok {
+		return n
+	}
+	n := 0
+	for _, used := range values {
+		n = max(n, used+1)
+	}
+	values[value] = n
+	return n
+}
+
+// reservedFields - the numbers of the fields removed from a message
+func (l *protobufLock) reservedFields(s *CodeStruct) []int {
+	reserved := make([]int, 0)
+	for name, n := range l.Messages[protobufTypeName(s.Name)] {
+		if !slices.ContainsFunc(s.Fields, func(f *CodeStructField) bool { return f.Name == name }) {
+			reserved = append(reserved, n)
+		}
+	}
+	slices.Sort(reserved)
+	return reserved
+}
+
+// reservedEnumValues - the numbers of the values removed from an enum, except 0 which is always declared
+func (l *protobufLock) reservedEnumValues(e *CodeEnum) []int {
+	reserved := make([]int, 0)
+	for value, n := range l.Enums[protobufTypeName(e.Name)] {
+		if n != 0 && !slices.Contains(e.Values, value) {
+			reserved = append(reserved, n)
+		}
+	}
+	slices.Sort(reserved)
+	return reserved
+}
+
+// protobufTypeName - the name of the message or the enum of a struct, an enum or a table
+func protobufTypeName(name string) string {
+	return pascal(name)
+}
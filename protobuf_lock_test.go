@@ -0,0 +1,36 @@
+package nestcsv
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProtobufLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "protobuf.lock.yaml")
+	s := &CodeStruct{Name: "items", Fields: []*CodeStructField{{Name: "ID"}, {Name: "Name"}, {Name: "Price"}}}
+	if err := withProtobufLock(nil, path, func(lock *protobufLock) error {
+		lock.assignStruct(s)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// reorder, remove and add the columns
+	s.Fields = []*CodeStructField{{Name: "Price"}, {Name: "ID"}, {Name: "Count"}}
+	if err := withProtobufLock(nil, path, func(lock *protobufLock) error {
+		got := make([]int, 0, len(s.Fields))
+		for _, field := range s.Fields {
+			got = append(got, lock.fieldNumber(s, field))
+		}
+		if want := []int{3, 1, 4}; !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected field numbers: %v, want %v", got, want)
+		}
+		if got := lock.reservedFields(s); !reflect.DeepEqual(got, []int{2}) {
+			t.Errorf("unexpected reserved fields: %v", got)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
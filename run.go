@@ -1,14 +1,92 @@
 package nestcsv
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"golang.org/x/sync/errgroup"
-	"sync"
 )
 
 func Generate(config *Config) error {
-	out := make(chan *TableData, 1000)
-	errStop := make(chan error, 1)
+	return GenerateContext(context.Background(), config)
+}
+
+// GenerateContext - Generate, which stops when the context is done
+func GenerateContext(ctx context.Context, config *Config) error {
+	var cache *buildCache
+	if config.CacheFile != "" {
+		var err error
+		if cache, err = loadBuildCache(config); err != nil {
+			return err
+		}
+	}
+
+	files := NewFileSink(false)
+	tableDatas, err := collectTables(ctx, files, config.Datasources, config.AllErrors)
+	if err == nil || config.AllErrors {
+		// collectErr - the datasource error kept to be reported with the others, if config.AllErrors is set
+		collectErr := err
+		err = generateTables(ctx, files, config, tableDatas, collectErr, nil, cache)
+	}
+	if err == nil {
+		_, err = files.writeManifests(config, config.NoPrune)
+	}
+	if cache != nil {
+		if err != nil {
+			cache.remove()
+			return err
+		}
+		return cache.save(config)
+	}
+	return err
+}
+
+// Result - the files generated by Run, by the path Generate writes to
+type Result struct {
+	Files map[string][]byte
+}
+
+// Run - generates into memory and writes nothing, to run nestcsv as a library, e.g. to preview the changes
+//
+//	The cache file and the manifests are not used, and the protobuf lock file is read if it exists.
+//	The runs can be concurrent, as long as they don't share a config.
+func Run(ctx context.Context, config *Config) (*Result, error) {
+	files, _, err := generateInMemory(ctx, config, false)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Files: files.data}, nil
+}
+
+// generateInMemory - generates without the cache, capturing the files and the manifests if manifests is set,
+// and returns the files to be removed by the manifests
+func generateInMemory(ctx context.Context, config *Config, manifests bool) (*FileSink, []string, error) {
+	var (
+		files   = NewFileSink(true)
+		removed []string
+	)
+	tableDatas, err := collectTables(ctx, files, config.Datasources, config.AllErrors)
+	if err == nil || config.AllErrors {
+		collectErr := err
+		err = generateTables(ctx, files, config, tableDatas, collectErr, nil, nil)
+	}
+	if err == nil && manifests {
+		removed, err = files.writeManifests(config, config.NoPrune)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return files, removed, nil
+}
+
+// collectTables - collects every table of the datasources, the tables collected before an error are returned too
+//
+//	The errors of every datasource are joined if allErrors is set, otherwise the first one stops the others.
+func collectTables(ctx context.Context, files *FileSink, datasources []DatasourceConfig, allErrors bool) ([]*TableData, error) {
+	var (
+		out     = make(chan *TableData, 1000)
+		errStop = make(chan error, 1)
+	)
 
 	go func() {
 		defer func() {
@@ -18,62 +96,145 @@ func Generate(config *Config) error {
 		}()
 		defer close(out)
 
-		var wg errgroup.Group
-		for _, datasource := range config.Datasources {
+		var (
+			wg, groupCtx = errgroup.WithContext(ctx)
+			errs         = make([]error, len(datasources))
+		)
+		for i, datasource := range datasources {
 			wg.Go(func() error {
-				return datasource.Collect(out)
+				if err := groupCtx.Err(); err != nil {
+					return err
+				}
+				err := datasource.Collect(groupCtx, files, out)
+				if allErrors {
+					errs[i] = err
+					return nil
+				}
+				return err
 			})
 		}
-		if err := wg.Wait(); err != nil {
+		err := wg.Wait()
+		if allErrors {
+			err = errors.Join(append(errs, ctx.Err())...)
+		}
+		if err != nil {
 			errStop <- fmt.Errorf("collect datasource: %w", err)
 			return
 		}
+		errStop <- nil
 	}()
 
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				errStop <- fmt.Errorf("panic during write output: %v", r)
+	tableDatas := make([]*TableData, 0)
+	for tableData := range out {
+		tableDatas = append(tableDatas, tableData)
+	}
+	return tableDatas, <-errStop
+}
+
+// generateTables - writes the outputs and generates the code of the collected tables
+//
+//	If rewrite is not nil, only the tables it reports are written, except for the aggregate outputs
+//	which need every table. The code is generated from every table anyway.
+//	If cache is not nil, the unchanged tables and code are skipped.
+func generateTables(ctx context.Context, files *FileSink, config *Config, tableDatas []*TableData, collectErr error, rewrite func(*TableData) bool, cache *buildCache) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic during write output: %v", r)
+		}
+	}()
+
+	// enums and refs can point to any table, so every table must be collected before writing
+	errs := make([]error, 0)
+	if collectErr != nil {
+		errs = append(errs, collectErr)
+	}
+
+	tableSet, err := NewTableSet(tableDatas)
+	if err != nil {
+		return errors.Join(append(errs, fmt.Errorf("failed to collect enums: %w", err))...)
+	}
+	if err := tableSet.ValidateRefs(config.AllErrors); err != nil {
+		if !config.AllErrors {
+			return fmt.Errorf("failed to validate refs: %w", err)
+		}
+		errs = append(errs, fmt.Errorf("failed to validate refs: %w", err))
+	}
+	if cache != nil {
+		if err := cache.prepare(config, tableSet); err != nil {
+			return err
+		}
+	}
+
+	var wg errgroup.Group
+	writeErrs := make([]error, len(tableSet.Tables))
+	for i, tableData := range tableSet.Tables {
+		wg.Go(func() error {
+			for j, output := range config.Outputs {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if rewrite != nil && !rewrite(tableData) && !output.IsAggregate() ||
+					cache != nil && cache.skipOutput(j, &output, tableData) {
+					files.skip(output.loaded)
+					continue
+				}
+				if err := output.Write(ctx, files, tableData, tableSet, config.AllErrors); err != nil {
+					if !config.AllErrors {
+						return err
+					}
+					writeErrs[i] = errors.Join(writeErrs[i], err)
+				}
 			}
-		}()
+			return nil
+		})
+	}
+	if err := wg.Wait(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	if err := errors.Join(writeErrs...); err != nil {
+		errs = append(errs, fmt.Errorf("failed to write output: %w", err))
+	}
+	for _, output := range config.Outputs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := output.Flush(ctx, files); err != nil {
+			if !config.AllErrors {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+			errs = append(errs, fmt.Errorf("failed to write output: %w", err))
+		}
+	}
 
-		var (
-			tableDatas []*TableData
-			mu         sync.Mutex
-			wg         errgroup.Group
-		)
-		for tableData := range out {
+	if len(tableSet.Tables) > 0 {
+		var wg errgroup.Group
+		genErrs := make([]error, len(config.Codegens))
+		for i, codegen := range config.Codegens {
 			wg.Go(func() error {
-				for _, output := range config.Outputs {
-					if err := output.Write(tableData); err != nil {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				code, err := AnalyzeTableCode(tableSet, codegen.Tags)
+				if err == nil && cache != nil && cache.skipCodegen(i, code) {
+					files.skip(codegen.loaded)
+				} else if err == nil {
+					err = codegen.loaded.Generate(ctx, files, code)
+				}
+				if err != nil {
+					if !config.AllErrors {
 						return err
 					}
+					genErrs[i] = err
 				}
-
-				mu.Lock()
-				tableDatas = append(tableDatas, tableData)
-				mu.Unlock()
 				return nil
 			})
 		}
 		if err := wg.Wait(); err != nil {
-			errStop <- fmt.Errorf("failed to write output: %w", err)
-			return
+			return fmt.Errorf("failed to generate code: %w", err)
 		}
-
-		if len(tableDatas) > 0 {
-			var wg errgroup.Group
-			for _, codegen := range config.Codegens {
-				wg.Go(func() error {
-					return codegen.Generate(tableDatas)
-				})
-			}
-			if err := wg.Wait(); err != nil {
-				errStop <- fmt.Errorf("failed to generate code: %w", err)
-				return
-			}
+		if err := errors.Join(genErrs...); err != nil {
+			errs = append(errs, fmt.Errorf("failed to generate code: %w", err))
 		}
-		errStop <- nil
-	}()
-	return <-errStop
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,124 @@
+package nestcsv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	rootDir := t.TempDir()
+	memory := &TableWriterMemory{}
+	newConfig := func() *Config {
+		return &Config{
+			Datasources: []DatasourceConfig{NewDatasourceConfig(&DatasourceMemory{Tables: []MemoryTable{
+				{File: "items.csv", Rows: [][]string{{""}, {"all"}, {"ID"}, {"int"}, {""}, {"1"}, {"2"}}},
+				{File: "shops.tsv", Reader: strings.NewReader("\t\nall\tall\nID\tName\nint\tstring\n\t\n1\tmain\n")},
+			}})},
+			Outputs: []OutputConfig{
+				NewOutputConfig(&TableWriterJSON{RootDir: filepath.Join(rootDir, "json")}, "all"),
+				NewOutputConfig(memory, "all"),
+			},
+			Codegens: []CodegenConfig{
+				NewCodegenConfig(&CodegenGo{RootDir: filepath.Join(rootDir, "go"), PackageName: "table"}, "all"),
+			},
+		}
+	}
+
+	result, err := Run(context.Background(), newConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(result.Files[filepath.Join(rootDir, "json", "shops.json")]); got != `[{"ID":1,"Name":"main"}]` {
+		t.Errorf("shops.json = %s", got)
+	}
+	if _, ok := result.Files[filepath.Join(rootDir, "go", "items.go")]; !ok {
+		t.Errorf("items.go is not generated")
+	}
+	if len(memory.Values()) != 2 {
+		t.Errorf("values = %v", memory.Values())
+	}
+	if entries, _ := os.ReadDir(rootDir); len(entries) != 0 {
+		t.Errorf("Run wrote %d files", len(entries))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Run(ctx, newConfig()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run with a canceled context = %v", err)
+	}
+}
+
+// barrierWriter - a custom output waiting for the other run to write too, so the runs must be concurrent
+type barrierWriter struct {
+	rootDir string
+	barrier *sync.WaitGroup
+}
+
+func (e *barrierWriter) Write(ctx context.Context, files *FileSink, name string, value any) error {
+	e.barrier.Done()
+	done := make(chan struct{})
+	go func() {
+		e.barrier.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		return errors.New("the runs are not concurrent")
+	}
+
+	file, err := files.Create(e.rootDir, name, "txt")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprint(file, value)
+	return err
+}
+
+func TestRunConcurrent(t *testing.T) {
+	var barrier sync.WaitGroup
+	barrier.Add(2)
+	newConfig := func(name string) *Config {
+		return &Config{
+			Datasources: []DatasourceConfig{NewDatasourceConfig(&DatasourceMemory{Tables: []MemoryTable{
+				{File: name + ".csv", Rows: [][]string{{""}, {"all"}, {"ID"}, {"int"}, {""}, {"1"}}},
+			}})},
+			Outputs: []OutputConfig{NewOutputConfig(&barrierWriter{rootDir: name, barrier: &barrier}, "all")},
+		}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		results = make([]*Result, 2)
+		errs    = make([]error, 2)
+	)
+	for i, name := range []string{"items", "shops"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = Run(context.Background(), newConfig(name))
+		}()
+	}
+	wg.Wait()
+
+	for i, name := range []string{"items", "shops"} {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		path := filepath.Join(name, name+".txt")
+		if got := string(results[i].Files[path]); got != "[map[ID:1]]" {
+			t.Errorf("%s = %q", path, got)
+		}
+		if len(results[i].Files) != 1 {
+			t.Errorf("the files of %s = %d, want 1", name, len(results[i].Files))
+		}
+	}
+}
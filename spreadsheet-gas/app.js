@@ -22,7 +22,10 @@ function readSheetsAsCSV(res, fileId) {
     }
     const values = sheet.getDataRange().getValues();
     const csvLines = values.map(row => row.map(cell => escapeCSVCell(cell, tz)).join(","));
-    res[sheet.getName()] = csvLines.join("\n");
+    res[sheet.getName()] = {
+      spreadsheet: spreadsheet.getName().replace(/\//g, "_"),
+      csv: csvLines.join("\n"),
+    };
   }
 }
 
@@ -73,8 +76,9 @@ function doGet(e) {
   }
   
   const csvBlobs = [];
-  for (const [name, csv] of Object.entries(res)) {
-    csvBlobs.push(Utilities.newBlob(csv, "text/csv", name + ".csv"));
+  for (const [name, { spreadsheet, csv }] of Object.entries(res)) {
+    // the spreadsheet name is kept as the directory to locate the errors
+    csvBlobs.push(Utilities.newBlob(csv, "text/csv", spreadsheet + "/" + name + ".csv"));
   }
   const zipBlob = Utilities.zip(csvBlobs, "cricket-tables.zip");
   const zipBase64 = Utilities.base64Encode(zipBlob.getBytes());
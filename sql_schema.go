@@ -0,0 +1,316 @@
+package nestcsv
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	sqlDialectPostgres = "postgres"
+	sqlDialectMySQL    = "mysql"
+	sqlDialectSQLite   = "sqlite"
+)
+
+// sqlTimeLayout - the text of a time value in UTC, which every dialect parses
+const sqlTimeLayout = "2006-01-02 15:04:05.999999"
+
+type sqlColumnKind int
+
+const (
+	sqlColumnValue     sqlColumnKind = iota
+	sqlColumnKey                     // the key of a map table without the id field
+	sqlColumnRowIndex                // the index of a row of a list table without the id field
+	sqlColumnParentKey               // refers to the primary key of the parent table
+	sqlColumnOrdinal                 // the index of an element of a multi-line array
+)
+
+// sqlTable - a table, or a child table normalized from a multi-line array of structs
+type sqlTable struct {
+	Name       string
+	Columns    []*sqlColumn
+	PrimaryKey []*sqlColumn
+	ForeignKey []*sqlColumn // the columns referring to the primary key of the parent
+	Parent     *sqlTable
+	Children   []*sqlTable
+	path       []string // the field names of the array from the parent row
+	arrays     []sqlArray
+}
+
+// sqlArray - a multi-line array found while flattening, added as a child once the primary key is known
+type sqlArray struct {
+	s    *CodeStruct
+	name string
+	path []string
+}
+
+type sqlColumn struct {
+	Name       string
+	Type       FieldType
+	IsNullable bool
+	IsJSON     bool // a json field or a cell array
+	Enum       *CodeEnum
+	kind       sqlColumnKind
+	path       []string // the field names from the row of the table, the nested structs are flattened
+}
+
+// newSQLTables - maps a table to the sql tables, the parent comes before its children
+//
+//	The id field is the primary key, a table without it has a key or a row index column instead.
+//	A nested struct is flattened into the columns prefixed by the field name, a multi-line array
+//	of structs becomes a child table keyed by the parent key and the ordinal, and a cell array
+//	is a json column.
+func newSQLTables(file *CodeFile) []*sqlTable {
+	table := &sqlTable{Name: file.Name}
+	switch {
+	case file.IDField != nil:
+	case file.IsMap:
+		table.addKey(&sqlColumn{Name: "_id", Type: file.IDFieldType, kind: sqlColumnKey})
+	default:
+		table.addKey(&sqlColumn{Name: "_row", Type: FieldTypeInt, kind: sqlColumnRowIndex})
+	}
+	table.flatten(file.Struct, "", nil, false)
+	if file.IDField != nil {
+		table.PrimaryKey = []*sqlColumn{table.Columns[0]}
+	}
+	table.addChildren()
+	return table.tables()
+}
+
+func (t *sqlTable) addKey(column *sqlColumn) {
+	t.Columns = append(t.Columns, column)
+	t.PrimaryKey = append(t.PrimaryKey, column)
+}
+
+func (t *sqlTable) flatten(s *CodeStruct, prefix string, path []string, nullable bool) {
+	for _, field := range s.Fields {
+		name := prefix + field.Name
+		fieldPath := append(slices.Clone(path), field.Name)
+		switch {
+		case field.IsArray && field.Type == FieldTypeStruct:
+			t.arrays = append(t.arrays, sqlArray{s: field.StructRef, name: name, path: fieldPath})
+		case field.Type == FieldTypeStruct:
+			t.flatten(field.StructRef, name+"_", fieldPath, nullable || field.IsNullable)
+		default:
+			t.Columns = append(t.Columns, &sqlColumn{
+				Name:       name,
+				Type:       field.Type,
+				IsNullable: nullable || field.IsNullable || (field.Type == FieldTypeJSON && !field.IsArray),
+				IsJSON:     field.IsArray || field.Type == FieldTypeJSON,
+				Enum:       field.EnumRef,
+				path:       fieldPath,
+			})
+		}
+	}
+}
+
+func (t *sqlTable) addChildren() {
+	for _, array := range t.arrays {
+		t.addChild(array)
+	}
+	t.arrays = nil
+}
+
+func (t *sqlTable) addChild(array sqlArray) {
+	child := &sqlTable{
+		Name:   t.Name + "_" + array.name,
+		Parent: t,
+		path:   array.path,
+	}
+	for _, key := range t.PrimaryKey {
+		column := &sqlColumn{Name: key.Name, Type: key.Type, kind: sqlColumnParentKey}
+		switch key.kind {
+		case sqlColumnParentKey:
+		case sqlColumnOrdinal:
+			column.Name = t.Name + key.Name
+		default:
+			column.Name = t.Name + "_" + key.Name
+		}
+		child.addKey(column)
+		child.ForeignKey = append(child.ForeignKey, column)
+	}
+	child.addKey(&sqlColumn{Name: "_ordinal", Type: FieldTypeInt, kind: sqlColumnOrdinal})
+	child.flatten(array.s, "", nil, false)
+	child.addChildren()
+	t.Children = append(t.Children, child)
+}
+
+func (t *sqlTable) tables() []*sqlTable {
+	tables := []*sqlTable{t}
+	for _, child := range t.Children {
+		tables = append(tables, child.tables()...)
+	}
+	return tables
+}
+
+// sqlRows - the column values of the rows of each sql table, in the order of the columns
+type sqlRows map[*sqlTable][][]any
+
+// newSQLRows - splits a table value produced by TableParser.Marshal into the rows of its sql tables
+func newSQLRows(table *sqlTable, value any) (sqlRows, error) {
+	rows := make(sqlRows)
+	switch v := value.(type) {
+	case []map[string]any:
+		for i, row := range v {
+			rows.collect(table, i, nil, row)
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			var index any = key
+			if in(table.PrimaryKey[0].Type, FieldTypeInt, FieldTypeLong) {
+				n, err := strconv.ParseInt(key, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid map key: %s, %w", key, err)
+				}
+				index = n
+			}
+			row, _ := v[key].(map[string]any)
+			rows.collect(table, index, nil, row)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected table value: %T", value)
+	}
+	return rows, nil
+}
+
+// collect - index is the map key or the row index of a table without the id field, or the ordinal of a child
+func (r sqlRows) collect(t *sqlTable, index any, parentKey []any, container map[string]any) {
+	row := make([]any, len(t.Columns))
+	var key []any
+	for i, column := range t.Columns {
+		switch column.kind {
+		case sqlColumnKey, sqlColumnRowIndex, sqlColumnOrdinal:
+			row[i] = index
+		case sqlColumnParentKey:
+			row[i] = parentKey[len(key)]
+		default:
+			row[i] = sqlLookup(container, column.path)
+		}
+		if slices.Contains(t.PrimaryKey, column) {
+			key = append(key, row[i])
+		}
+	}
+	r[t] = append(r[t], row)
+
+	for _, child := range t.Children {
+		switch elems := sqlLookup(container, child.path).(type) {
+		case []map[string]any:
+			for i, elem := range elems {
+				r.collect(child, i, key, elem)
+			}
+		case []any:
+			for i, elem := range elems {
+				elem, _ := elem.(map[string]any)
+				r.collect(child, i, key, elem)
+			}
+		}
+	}
+}
+
+// sqlLookup - the value at the path of the nested structs, nil if a struct on the way is null or omitted
+func sqlLookup(container map[string]any, path []string) any {
+	var value any = container
+	for _, name := range path {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = m[name]
+	}
+	if v, ok := value.(*any); ok {
+		if v == nil {
+			return nil
+		}
+		return *v
+	}
+	return value
+}
+
+// sqlDialect - the dialect specific syntax
+type sqlDialect string
+
+func newSQLDialect(name string) (sqlDialect, error) {
+	switch name {
+	case sqlDialectPostgres, "postgresql", "":
+		return sqlDialectPostgres, nil
+	case sqlDialectMySQL:
+		return sqlDialectMySQL, nil
+	case sqlDialectSQLite, "sqlite3":
+		return sqlDialectSQLite, nil
+	default:
+		return "", fmt.Errorf("unsupported sql dialect: %s", name)
+	}
+}
+
+func (d sqlDialect) quote(name string) string {
+	if d == sqlDialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (d sqlDialect) quoteList(columns []*sqlColumn) string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = d.quote(column.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// columnType - a string key of mysql is a VARCHAR, since a TEXT can't be indexed without a length
+//
+//	sqlite has the storage classes only, a time is a TEXT of "YYYY-MM-DD HH:MM:SS" as its date functions read
+func (d sqlDialect) columnType(table *sqlTable, column *sqlColumn) string {
+	if d == sqlDialectSQLite {
+		switch {
+		case column.IsJSON:
+			return "TEXT"
+		case in(column.Type, FieldTypeInt, FieldTypeLong, FieldTypeBool):
+			return "INTEGER"
+		case column.Type == FieldTypeFloat:
+			return "REAL"
+		default:
+			return "TEXT"
+		}
+	}
+	if column.IsJSON {
+		if d == sqlDialectPostgres {
+			return "JSONB"
+		}
+		return "JSON"
+	}
+	switch column.Type {
+	case FieldTypeInt:
+		return "INTEGER"
+	case FieldTypeLong:
+		return "BIGINT"
+	case FieldTypeFloat:
+		if d == sqlDialectPostgres {
+			return "DOUBLE PRECISION"
+		}
+		return "DOUBLE"
+	case FieldTypeBool:
+		return "BOOLEAN"
+	case FieldTypeTime:
+		if d == sqlDialectMySQL {
+			return "DATETIME"
+		}
+		return "TIMESTAMP"
+	default:
+		if d == sqlDialectMySQL && slices.Contains(table.PrimaryKey, column) {
+			return "VARCHAR(255)"
+		}
+		return "TEXT"
+	}
+}
+
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,47 @@
+package nestcsv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSQLTables(t *testing.T) {
+	reward := &CodeStruct{Name: "items_Rewards", Fields: []*CodeStructField{
+		{Name: "Type", Type: FieldTypeString},
+		{Name: "Count", Type: FieldTypeInt},
+	}}
+	file := &CodeFile{
+		Name: "items",
+		Struct: &CodeStruct{Name: "items", Fields: []*CodeStructField{
+			{Name: "ID", Type: FieldTypeString},
+			{Name: "Rewards", Type: FieldTypeStruct, IsArray: true, StructRef: reward},
+			{Name: "Tags", Type: FieldTypeString, IsArray: true},
+		}},
+	}
+	file.IDField = file.Struct.Fields[0]
+
+	tables := newSQLTables(file)
+	if len(tables) != 2 {
+		t.Fatalf("unexpected tables: %d", len(tables))
+	}
+	names := func(columns []*sqlColumn) []string {
+		var names []string
+		for _, column := range columns {
+			names = append(names, column.Name)
+		}
+		return names
+	}
+	if got := names(tables[0].Columns); !reflect.DeepEqual(got, []string{"ID", "Tags"}) {
+		t.Errorf("unexpected columns: %v", got)
+	}
+	child := tables[1]
+	if child.Name != "items_Rewards" {
+		t.Errorf("unexpected child table: %s", child.Name)
+	}
+	if got := names(child.PrimaryKey); !reflect.DeepEqual(got, []string{"items_ID", "_ordinal"}) {
+		t.Errorf("unexpected primary key: %v", got)
+	}
+	if got := names(child.ForeignKey); !reflect.DeepEqual(got, []string{"items_ID"}) {
+		t.Errorf("unexpected foreign key: %v", got)
+	}
+}
@@ -0,0 +1,142 @@
+package nestcsv
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldConstraint - the default value and the checks of a column, declared in the constraint row
+//
+//	Rules are separated by ; (e.g. required;min=0;max=100), and \; is a ; in a rule (e.g. regex=^[a-z\;]+$)
+//	- default=<value> : replaces an empty cell
+//	- required        : rejects an empty cell
+//	- min=<n>, max=<n> : the range of a number
+//	- regex=<pattern>  : the pattern a non-empty value must match
+//	- len<=<n>, len>=<n> : the range of the length of a non-empty value
+type FieldConstraint struct {
+	Default  *string
+	Required bool
+	Min      *float64
+	Max      *float64
+	Regex    *regexp.Regexp
+	MinLen   *int
+	MaxLen   *int
+}
+
+func newFieldConstraint(cell string, typeCell string) (*FieldConstraint, error) {
+	cell = strings.TrimSpace(cell)
+	if cell == "" {
+		return nil, nil
+	}
+
+	var (
+		c               FieldConstraint
+		fieldType, _, _ = newFieldType(typeCell)
+	)
+	for _, rule := range splitConstraintRules(cell) {
+		rule = strings.TrimSpace(rule)
+		switch {
+		case rule == "":
+			continue
+
+		case rule == "required":
+			c.Required = true
+
+		case strings.HasPrefix(rule, "default="):
+			v := strings.TrimPrefix(rule, "default=")
+			c.Default = &v
+
+		case strings.HasPrefix(rule, "min="), strings.HasPrefix(rule, "max="):
+			if !fieldType.isNumber() {
+				return nil, fmt.Errorf("min/max is only for number types: %s", rule)
+			}
+			v, err := strconv.ParseFloat(rule[len("min="):], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint: %s, %w", rule, err)
+			}
+			if strings.HasPrefix(rule, "min=") {
+				c.Min = &v
+			} else {
+				c.Max = &v
+			}
+
+		case strings.HasPrefix(rule, "regex="):
+			re, err := regexp.Compile(strings.TrimPrefix(rule, "regex="))
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint: %s, %w", rule, err)
+			}
+			c.Regex = re
+
+		case strings.HasPrefix(rule, "len<="), strings.HasPrefix(rule, "len>="):
+			v, err := strconv.Atoi(rule[len("len<="):])
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint: %s, %w", rule, err)
+			}
+			if strings.HasPrefix(rule, "len<=") {
+				c.MaxLen = &v
+			} else {
+				c.MinLen = &v
+			}
+
+		default:
+			return nil, fmt.Errorf("unknown constraint: %s", rule)
+		}
+	}
+	return &c, nil
+}
+
+// splitConstraintRules - splits the rules by ;, keeping the escaped \; as a ; of the rule
+func splitConstraintRules(cell string) []string {
+	var (
+		rules []string
+		rule  strings.Builder
+	)
+	for i := 0; i < len(cell); i++ {
+		switch {
+		case cell[i] == '\\' && i+1 < len(cell) && cell[i+1] == ';':
+			rule.WriteByte(';')
+			i++
+		case cell[i] == ';':
+			rules = append(rules, rule.String())
+			rule.Reset()
+		default:
+			rule.WriteByte(cell[i])
+		}
+	}
+	return append(rules, rule.String())
+}
+
+// Check - checks a non-empty value, which is an element if the field is a cell array
+func (c *FieldConstraint) Check(cell string, value any) error {
+	if c.Min != nil || c.Max != nil {
+		var n float64
+		switch v := value.(type) {
+		case int:
+			n = float64(v)
+		case int64:
+			n = float64(v)
+		case float64:
+			n = v
+		}
+		if c.Min != nil && n < *c.Min {
+			return fmt.Errorf("less than min: %v < %v", value, *c.Min)
+		}
+		if c.Max != nil && n > *c.Max {
+			return fmt.Errorf("greater than max: %v > %v", value, *c.Max)
+		}
+	}
+	if c.Regex != nil && !c.Regex.MatchString(cell) {
+		return fmt.Errorf("not matched with regex: %s", c.Regex)
+	}
+	length := utf8.RuneCountInString(cell)
+	if c.MinLen != nil && length < *c.MinLen {
+		return fmt.Errorf("shorter than len>=%d", *c.MinLen)
+	}
+	if c.MaxLen != nil && length > *c.MaxLen {
+		return fmt.Errorf("longer than len<=%d", *c.MaxLen)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package nestcsv
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestFieldConstraint(t *testing.T) {
+	c, err := newFieldConstraint("required; default=5; min=0; max=100", "int")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Required || c.Default == nil || *c.Default != "5" {
+		t.Errorf("unexpected constraint: %+v", c)
+	}
+	if err := c.Check("50", 50); err != nil {
+		t.Error(err)
+	}
+	if err := c.Check("101", 101); err == nil {
+		t.Error("expected max error")
+	}
+
+	c, err = newFieldConstraint("regex=^[A-Z_]+$;len<=4", "string")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Check("GOLD", "GOLD"); err != nil {
+		t.Error(err)
+	}
+	if err := c.Check("gold", "gold"); err == nil {
+		t.Error("expected regex error")
+	}
+	if err := c.Check("GOLDS", "GOLDS"); err == nil {
+		t.Error("expected len error")
+	}
+
+	c, err = newFieldConstraint(`regex=^[a-z]+(\;[a-z]+)*$; default=a\;b`, "string")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Default == nil || *c.Default != "a;b" {
+		t.Errorf("unexpected default: %v", c.Default)
+	}
+	if err := c.Check("gold;gem", "gold;gem"); err != nil {
+		t.Error(err)
+	}
+	if err := c.Check("gold;", "gold;"); err == nil {
+		t.Error("expected regex error")
+	}
+
+	if _, err := newFieldConstraint("min=0", "string"); err == nil {
+		t.Error("expected min error for string")
+	}
+	if _, err := newFieldConstraint("unique", "int"); err == nil {
+		t.Error("expected unknown constraint error")
+	}
+}
+
+func TestParseTableDataShortRows(t *testing.T) {
+	// the trailing empty cells are trimmed, as excelize does
+	rows := [][]string{
+		{"constraint_row=true"},
+		{"all", "all", "all"},
+		{"ID", "Name", "Count"},
+		{"int", "string"},
+		{},
+		{"", "required"},
+		{"1", "sword"},
+	}
+	td, err := ParseTableData("items.xlsx", "items", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(td.FieldConstraints) != 3 || td.FieldConstraints[1] == nil || !td.FieldConstraints[1].Required || td.FieldConstraints[2] != nil {
+		t.Errorf("unexpected constraints: %v", td.FieldConstraints)
+	}
+	if len(td.DataRows) != 1 || len(td.DataRows[0]) != 3 || td.DataRows[0][2] != "" {
+		t.Errorf("unexpected rows: %q", td.DataRows)
+	}
+
+	path := filepath.Join(t.TempDir(), "items.xlsx")
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", "items"); err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		values := make([]any, len(row))
+		for j, cell := range row {
+			values[j] = cell
+		}
+		if err := file.SetSheetRow("items", fmt.Sprintf("A%d", i+1), &values); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := file.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+	out := make(chan *TableData, 1)
+	if err := (&DatasourceExcel{}).CollectFile(context.Background(), nil, path, out); err != nil {
+		t.Fatal(err)
+	}
+	if td := <-out; len(td.DataRows) != 1 || td.DataRows[0][1] != "sword" {
+		t.Errorf("unexpected rows: %q", td.DataRows)
+	}
+}
@@ -3,7 +3,6 @@ package nestcsv
 import (
 	"fmt"
 	"path/filepath"
-	"slices"
 	"strings"
 )
 
@@ -14,38 +13,80 @@ const (
 	TableFieldTypeRow = 3
 	TableDataStartRow = 5
 
+	// TableConstraintRow - the optional row of the field constraints, the data starts from the next row if it's enabled
+	TableConstraintRow = 5
+
 	TableFieldIndexCol = 0
 )
 
 var ErrSkipTable = fmt.Errorf("skip table")
 
 type TableData struct {
-	Name       string
+	Name string
+	// File, Sheet - where the table is read from, to locate errors. Sheet is empty for csv files
+	File       string
+	Sheet      string
 	Metadata   *TableMetadata
 	Columns    int
 	FieldTags  [][]string
 	FieldNames []string
 	FieldTypes []string
-	DataRows   [][]string
+	// FieldConstraints - nil if the constraint row is disabled, or an element is nil if the column has no constraint
+	FieldConstraints []*FieldConstraint
+	DataRows         [][]string
+	// SourceRows, SourceColumns - the 0-based row of each data row and column of each field in the sheet,
+	// since the skipped rows and the dropped columns are removed
+	SourceRows    []int
+	SourceColumns []int
+
+	rows [][]string // the rows of the sheet as read, hashed by the build cache
 }
 
-func ParseTableData(name string, csvData [][]string) (*TableData, error) {
-	tableName := strings.TrimSuffix(filepath.Base(name), ".csv")
+// ParseTableData - parses the rows of a sheet, the table is named after the sheet, or the file if the sheet is empty
+func ParseTableData(file, sheet string, csvData [][]string) (*TableData, error) {
+	tableName := sheet
+	if tableName == "" {
+		tableName = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
 	if strings.HasPrefix(tableName, "#") {
 		return nil, ErrSkipTable
 	}
 
+	table := &TableData{
+		Name:  tableName,
+		File:  file,
+		Sheet: sheet,
+	}
+
 	if len(csvData) < TableDataStartRow {
-		return nil, fmt.Errorf("invalid table data: %s", name)
+		return nil, newTableError(table, -1, -1, fmt.Errorf("invalid table data, the header rows are missing"))
+	}
+
+	metadataQuery := TableMetadataQuery(cellAt(csvData[TableMetadataRow], 0))
+	metadata, err := metadataQuery.Decode()
+	if err != nil {
+		return nil, newTableError(table, TableMetadataRow, 0, fmt.Errorf("failed to decode table metadata query: %w", err))
+	}
+
+	dataStartRow := metadata.dataStartRow()
+	if metadata.ConstraintRow {
+		if len(csvData) < dataStartRow {
+			return nil, newTableError(table, TableConstraintRow, -1, fmt.Errorf("invalid table data, the constraint row is missing"))
+		}
 	}
 
 	var (
-		columns     = len(csvData[TableFieldNameRow])
-		dropColumns = make([]int, 0)
-		fieldTags   = make([][]string, 0, columns)
-		fieldNames  = make([]string, 0, columns)
-		fieldTypes  = make([]string, 0, columns)
+		columns          = len(csvData[TableFieldNameRow])
+		dropColumns      = make([]int, 0)
+		fieldTags        = make([][]string, 0, columns)
+		fieldNames       = make([]string, 0, columns)
+		fieldTypes       = make([]string, 0, columns)
+		fieldConstraints []*FieldConstraint
+		sourceColumns    = make([]int, 0, columns)
 	)
+	if metadata.ConstraintRow {
+		fieldConstraints = make([]*FieldConstraint, 0, columns)
+	}
 
 	for col := 0; col < columns; col++ {
 		fieldName := strings.ReplaceAll(strings.TrimSpace(csvData[TableFieldNameRow][col]), "\n", "")
@@ -53,68 +94,85 @@ func ParseTableData(name string, csvData [][]string) (*TableData, error) {
 			dropColumns = append(dropColumns, col)
 		} else {
 			tags := make([]string, 0)
-			if tagsCell := csvData[TableFieldTagRow][col]; tagsCell != "" {
+			if tagsCell := cellAt(csvData[TableFieldTagRow], col); tagsCell != "" {
 				tags = strings.Split(tagsCell, ",")
 			}
 			fieldTags = append(fieldTags, tags)
 			fieldNames = append(fieldNames, fieldName)
-			fieldTypes = append(fieldTypes, csvData[TableFieldTypeRow][col])
+			fieldTypes = append(fieldTypes, cellAt(csvData[TableFieldTypeRow], col))
+			sourceColumns = append(sourceColumns, col)
+
+			if metadata.ConstraintRow {
+				constraint, err := newFieldConstraint(cellAt(csvData[TableConstraintRow], col), cellAt(csvData[TableFieldTypeRow], col))
+				if err != nil {
+					tableErr := newTableError(table, TableConstraintRow, col, fmt.Errorf("invalid field constraint: %w", err))
+					tableErr.Field = fieldName
+					return nil, tableErr
+				}
+				fieldConstraints = append(fieldConstraints, constraint)
+			}
 		}
 	}
 
 	columns -= len(dropColumns)
 	if columns == 0 {
-		return nil, fmt.Errorf("no columns in the csv file: %s", name)
+		return nil, newTableError(table, TableFieldNameRow, -1, fmt.Errorf("no columns in the table"))
 	}
 
 	dataRows := make([][]string, 0, len(csvData))
-	for _, row := range csvData[TableDataStartRow:] {
-		id := row[TableFieldIndexCol]
+	sourceRows := make([]int, 0, len(csvData))
+	for rowIdx, row := range csvData[dataStartRow:] {
+		id := cellAt(row, TableFieldIndexCol)
 		if id == "" || strings.HasPrefix(id, "#") {
 			continue
 		}
 
 		dataRow := make([]string, columns)
-		i := 0
-		for col, cell := range row {
-			if !slices.Contains(dropColumns, col) {
-				dataRow[i] = cell
-				i++
-			}
+		for i, col := range sourceColumns {
+			dataRow[i] = cellAt(row, col)
 		}
 		dataRows = append(dataRows, dataRow)
+		sourceRows = append(sourceRows, dataStartRow+rowIdx)
 	}
 
+	table.Columns = columns
+	table.FieldTags = fieldTags
+	table.FieldNames = fieldNames
+	table.FieldTypes = fieldTypes
+	table.FieldConstraints = fieldConstraints
+	table.DataRows = dataRows
+	table.SourceRows = sourceRows
+	table.SourceColumns = sourceColumns
+
 	idxName := fieldNames[TableFieldIndexCol]
 	if strings.Contains(idxName, ".") {
-		return nil, fmt.Errorf("invalid index field: %s, %s", tableName, idxName)
+		return nil, newTableFieldError(table, TableFieldNameRow, TableFieldIndexCol, fmt.Errorf("invalid index field: %s", idxName))
 	}
 	idxType := FieldType(fieldTypes[TableFieldIndexCol])
 	if !idxType.isValidIndexType() {
-		return nil, fmt.Errorf("invalid index field type: %s, %s, %s", tableName, idxName, idxType)
+		return nil, newTableFieldError(table, TableFieldTypeRow, TableFieldIndexCol, fmt.Errorf("invalid index field type: %s", idxType))
 	}
 
-	table := &TableData{
-		Name:       tableName,
-		Columns:    columns,
-		FieldTags:  fieldTags,
-		FieldNames: fieldNames,
-		FieldTypes: fieldTypes,
-		DataRows:   dataRows,
-	}
-
-	metadataQuery := TableMetadataQuery(csvData[TableMetadataRow][0])
-	metadata, err := metadataQuery.Decode()
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode table metadata query: %s, %w", name, err)
-	}
 	if err := metadata.Validate(table); err != nil {
-		return nil, fmt.Errorf("invalid table metadata: %s, %w", name, err)
+		return nil, newTableError(table, TableMetadataRow, 0, fmt.Errorf("invalid table metadata: %w", err))
 	}
 	table.Metadata = metadata
+	table.rows = csvData
 	return table, nil
 }
 
+// cellAt - the cell of the row, or empty if the row is shorter, since some datasources trim the trailing empty cells
+func cellAt(row []string, col int) string {
+	if col < len(row) {
+		return row[col]
+	}
+	return ""
+}
+
+func (d *TableData) IDFieldType() FieldType {
+	return FieldType(d.FieldTypes[TableFieldIndexCol])
+}
+
 func (d *TableData) CSV() [][]string {
 	return append([][]string{d.FieldNames, d.FieldTypes}, d.DataRows...)
 }
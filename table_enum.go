@@ -0,0 +1,78 @@
+package nestcsv
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+)
+
+var enumValueRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// TableEnum - the allowed values of an enum field type (e.g. enum:RewardType)
+//
+//	It is declared either by an enum sheet (as_enum=true), whose ID column lists the values,
+//	or by a metadata entry of any table (e.g. enum=RewardType:Gold|Gear|Gem).
+type TableEnum struct {
+	Name   string
+	Values []string
+}
+
+func (e *TableEnum) Validate() error {
+	if len(e.Values) == 0 {
+		return fmt.Errorf("enum %q has no values", e.Name)
+	}
+	for _, value := range e.Values {
+		if !enumValueRegex.MatchString(value) {
+			return fmt.Errorf("enum %q has an invalid value: %q", e.Name, value)
+		}
+	}
+	return nil
+}
+
+func (e *TableEnum) Contains(value string) bool {
+	return slices.Contains(e.Values, value)
+}
+
+// CollectTableEnums - collects the enums declared across all tables, keyed by name
+func CollectTableEnums(tableDatas []*TableData) (map[string]*TableEnum, error) {
+	enums := make(map[string]*TableEnum)
+	addEnum := func(enum *TableEnum) error {
+		if err := enum.Validate(); err != nil {
+			return err
+		}
+		if existing, ok := enums[enum.Name]; ok {
+			if !slices.Equal(existing.Values, enum.Values) {
+				return fmt.Errorf("enum %q has different values", enum.Name)
+			}
+			return nil
+		}
+		enums[enum.Name] = enum
+		return nil
+	}
+
+	for _, td := range tableDatas {
+		if td.Metadata.AsEnum {
+			enum := &TableEnum{Name: td.Name}
+			for _, row := range td.DataRows {
+				enum.Values = appendUnique(enum.Values, row[TableFieldIndexCol])
+			}
+			if err := addEnum(enum); err != nil {
+				return nil, fmt.Errorf("%s, %w", td.Name, err)
+			}
+		}
+
+		names := make([]string, 0, len(td.Metadata.Enums))
+		for name := range td.Metadata.Enums {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			enum := &TableEnum{Name: name, Values: td.Metadata.Enums.Values(name)}
+			if err := addEnum(enum); err != nil {
+				return nil, fmt.Errorf("%s, %w", td.Name, err)
+			}
+		}
+	}
+	return enums, nil
+}
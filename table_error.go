@@ -0,0 +1,162 @@
+package nestcsv
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TableError - a problem found in a table, located by the cell the designer sees in the sheet
+type TableError struct {
+	Table    string `json:"table,omitempty"`
+	File     string `json:"file,omitempty"`
+	Sheet    string `json:"sheet,omitempty"`
+	Cell     string `json:"cell,omitempty"` // A1 address
+	Field    string `json:"field,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Expected string `json:"expected,omitempty"` // the field type
+	Message  string `json:"message"`
+
+	row, column int  // 1-based, 0 if unknown
+	isData      bool // whether the cell is a data cell, not a header cell
+}
+
+// newTableError - row and column are the 0-based position in the sheet, or -1 if unknown
+func newTableError(td *TableData, row, column int, err error) *TableError {
+	return &TableError{
+		Table:   td.Name,
+		File:    td.File,
+		Sheet:   td.Sheet,
+		Cell:    cellAddress(row+1, column+1),
+		Message: err.Error(),
+		row:     max(row+1, 0),
+		column:  max(column+1, 0),
+	}
+}
+
+// newTableFieldError - an error of the header cell of a field, row is one of the header rows
+func newTableFieldError(td *TableData, row, col int, err error) *TableError {
+	tableErr := newTableError(td, row, td.SourceColumns[col], err)
+	tableErr.Field = td.FieldNames[col]
+	tableErr.Expected = td.FieldTypes[col]
+	return tableErr
+}
+
+// newTableCellError - an error of the data cell, rowIdx and col are the indices of DataRows
+func newTableCellError(td *TableData, rowIdx, col int, cell string, err error) *TableError {
+	tableErr := newTableError(td, td.SourceRows[rowIdx], td.SourceColumns[col], err)
+	tableErr.Field = td.FieldNames[col]
+	tableErr.Value = cell
+	tableErr.Expected = td.FieldTypes[col]
+	tableErr.isData = true
+	return tableErr
+}
+
+// Location - file!sheet!A1, each part is omitted if unknown (e.g. csv files have no sheet)
+func (e *TableError) Location() string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{e.File, e.Sheet, e.Cell} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 || (e.File == "" && e.Sheet == "") {
+		parts = append([]string{e.Table}, parts...)
+	}
+	return strings.Join(parts, "!")
+}
+
+func (e *TableError) Error() string {
+	location := e.Location()
+	switch {
+	case e.isData:
+		return fmt.Sprintf("%s: %s (field: %s, value: %q)", location, e.Message, e.Field, e.Value)
+	case e.Field != "":
+		return fmt.Sprintf("%s: %s (field: %s)", location, e.Message, e.Field)
+	case location != "":
+		return fmt.Sprintf("%s: %s", location, e.Message)
+	default:
+		return e.Message
+	}
+}
+
+// TableErrors - every problem found in a run, deduplicated
+type TableErrors []*TableError
+
+// CollectTableErrors - flattens a (joined) error into table errors, wrapping the errors without a cell location
+func CollectTableErrors(err error) TableErrors {
+	var (
+		ret     TableErrors
+		visited = make(map[string]struct{})
+		collect func(err error)
+	)
+	add := func(tableErr *TableError) {
+		// the same cell can fail for every output
+		key := tableErr.Error()
+		if _, ok := visited[key]; ok {
+			return
+		}
+		visited[key] = struct{}{}
+		ret = append(ret, tableErr)
+	}
+	collect = func(err error) {
+		var tableErr *TableError
+		switch e := err.(type) {
+		case *TableError:
+			add(e)
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				collect(inner)
+			}
+		default:
+			if errors.As(err, &tableErr) {
+				collect(errors.Unwrap(err))
+			} else {
+				add(&TableError{Message: err.Error()})
+			}
+		}
+	}
+	if err != nil {
+		collect(err)
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		a, b := ret[i], ret[j]
+		if a.Table != b.Table {
+			return a.Table < b.Table
+		}
+		if a.row != b.row {
+			return a.row < b.row
+		}
+		return a.column < b.column
+	})
+	return ret
+}
+
+func (e TableErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// cellAddress - returns the A1 address of a 1-based row and column
+func cellAddress(row, column int) string {
+	if row <= 0 || column <= 0 {
+		return ""
+	}
+	return columnName(column) + strconv.Itoa(row)
+}
+
+// columnName - returns the letters of a 1-based column (e.g. 1 -> A, 27 -> AA)
+func columnName(column int) string {
+	var name []byte
+	for column > 0 {
+		column--
+		name = append([]byte{byte('A' + column%26)}, name...)
+		column /= 26
+	}
+	return string(name)
+}
@@ -0,0 +1,113 @@
+package nestcsv
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+const (
+	ReportFormatTable = "table"
+	ReportFormatJSON  = "json"
+	ReportFormatSARIF = "sarif"
+)
+
+// WriteReport - writes the errors as a text table, a JSON array or a SARIF log
+func (e TableErrors) WriteReport(w io.Writer, format string) error {
+	switch format {
+	case ReportFormatTable, "":
+		return e.writeTable(w)
+	case ReportFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(e)
+	case ReportFormatSARIF:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(e.sarif())
+	default:
+		return fmt.Errorf("unknown report format: %s", format)
+	}
+}
+
+func (e TableErrors) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TABLE\tFILE\tSHEET\tCELL\tFIELD\tVALUE\tEXPECTED\tMESSAGE")
+	for _, err := range e {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%q\t%s\t%s\n", err.Table, err.File, err.Sheet, err.Cell, err.Field, err.Value, err.Expected, err.Message)
+	}
+	if _, err := fmt.Fprintf(tw, "\n%d error(s)\n", len(e)); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name           string `json:"name"`
+			InformationURI string `json:"informationUri"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifResult struct {
+	Level   string `json:"level"`
+	Message struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *sarifRegion `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+func (e TableErrors) sarif() *sarifLog {
+	var run sarifRun
+	run.Tool.Driver.Name = "nestcsv"
+	run.Tool.Driver.InformationURI = "https://github.com/unsafe9/nestcsv"
+	run.Results = make([]sarifResult, 0, len(e))
+
+	for _, err := range e {
+		result := sarifResult{Level: "error"}
+		result.Message.Text = err.Error()
+		if uri := cmp.Or(err.File, err.Sheet); uri != "" {
+			var location sarifLocation
+			location.PhysicalLocation.ArtifactLocation.URI = uri
+			if err.row > 0 && err.column > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{
+					StartLine:   err.row,
+					StartColumn: err.column,
+				}
+			}
+			result.Locations = append(result.Locations, location)
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	return &sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+}
@@ -0,0 +1,101 @@
+package nestcsv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCellAddress(t *testing.T) {
+	cases := map[string][2]int{
+		"A1":   {1, 1},
+		"D17":  {17, 4},
+		"Z3":   {3, 26},
+		"AA3":  {3, 27},
+		"AZ10": {10, 52},
+		"BA10": {10, 53},
+	}
+	for want, rc := range cases {
+		if got := cellAddress(rc[0], rc[1]); got != want {
+			t.Errorf("cellAddress(%d, %d) = %s, want %s", rc[0], rc[1], got, want)
+		}
+	}
+}
+
+func TestCollectTableErrors(t *testing.T) {
+	cellErr := &TableError{Table: "items", Cell: "B2", Message: "required", row: 2, column: 2}
+	err := errors.Join(
+		fmt.Errorf("failed to write output: %w", errors.Join(cellErr, cellErr)),
+		errors.New("collect datasource: broken file"),
+	)
+	tableErrs := CollectTableErrors(err)
+	if len(tableErrs) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(tableErrs), tableErrs)
+	}
+	if tableErrs[0].Message != "collect datasource: broken file" || tableErrs[1] != cellErr {
+		t.Errorf("unexpected errors: %v", tableErrs)
+	}
+}
+
+func TestTableCellErrorLocation(t *testing.T) {
+	rows := [][]string{
+		{""},
+		{"all", "all", "all", "all"},
+		{"ID", "#Memo", "Name", "Price"},
+		{"int", "string", "string", "int"},
+		{"", "", "", ""},
+		{"1", "", "a", "10"},
+		{"#2", "", "b", "x"},
+		{"3", "", "c", "x"},
+	}
+	td, err := ParseTableData("items.xlsx", "Sheet1", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := NewTableParser(td, nil)
+	fields, err := parser.ParseTableFields([]string{"all"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = parser.Marshal(fields)
+	var tableErr *TableError
+	if !errors.As(err, &tableErr) {
+		t.Fatalf("expected a table error, got %v", err)
+	}
+	if got := tableErr.Location(); got != "items.xlsx!Sheet1!D8" {
+		t.Errorf("unexpected location: %s", got)
+	}
+}
+
+func TestCollectAllErrors(t *testing.T) {
+	dir := t.TempDir()
+	broken := "constraint_row=true,\nall,all\nID,Name\nint,string\n,\n,min=0\n1,a\n"
+	for _, name := range []string{"items.csv", "shops.csv", "valid.csv"} {
+		content := broken
+		if name == "valid.csv" {
+			content = ",\nall,all\nID,Name\nint,string\n,\n1,a\n"
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config := &Config{
+		Datasources: []DatasourceConfig{NewDatasourceConfig(&DatasourceCSV{Patterns: []string{filepath.Join(dir, "*.csv")}})},
+		Outputs:     []OutputConfig{NewOutputConfig(&TableWriterJSON{RootDir: filepath.Join(dir, "json")}, "all")},
+		AllErrors:   true,
+	}
+
+	_, err := Run(context.Background(), config)
+	var files []string
+	for _, tableErr := range CollectTableErrors(err) {
+		files = append(files, filepath.Base(tableErr.File))
+	}
+	slices.Sort(files)
+	if !slices.Equal(files, []string{"items.csv", "shops.csv"}) {
+		t.Errorf("the errors of %v, want items.csv and shops.csv: %v", files, err)
+	}
+}
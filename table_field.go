@@ -14,14 +14,25 @@ const (
 	FieldTypeString FieldType = "string"
 	FieldTypeTime   FieldType = "time"
 	FieldTypeJSON   FieldType = "json"
+	FieldTypeEnum   FieldType = "enum"
+	FieldTypeRef    FieldType = "ref"
 	FieldTypeStruct FieldType = "struct"
 )
 
-func newFieldType(s string) (FieldType, bool) {
-	if strings.HasPrefix(s, "[]") {
-		return FieldType(s[len("[]"):]), true
+// newFieldType - parses a field type cell (e.g. "[]enum:RewardType") into the type, its parameter and whether it is a cell array
+func newFieldType(s string) (FieldType, string, bool) {
+	s = strings.TrimSuffix(s, "?")
+	isArray := strings.HasPrefix(s, "[]")
+	if isArray {
+		s = s[len("[]"):]
 	}
-	return FieldType(s), false
+	typ, param, _ := strings.Cut(s, ":")
+	return FieldType(typ), param, isArray
+}
+
+// isNullableFieldType - a field type cell with the ? suffix (e.g. "int?") leaves empty cells as null instead of the zero value
+func isNullableFieldType(s string) bool {
+	return strings.HasSuffix(s, "?")
 }
 
 func (t FieldType) String() string {
@@ -35,6 +46,14 @@ func (t FieldType) Array() FieldType {
 	return FieldType("[]" + t.String())
 }
 
+func (t FieldType) isNumber() bool {
+	switch t {
+	case FieldTypeInt, FieldTypeLong, FieldTypeFloat:
+		return true
+	}
+	return false
+}
+
 func (t FieldType) isValidIndexType() bool {
 	switch t {
 	case FieldTypeInt, FieldTypeLong, FieldTypeString:
@@ -46,8 +65,10 @@ func (t FieldType) isValidIndexType() bool {
 type TableField struct {
 	Name             string
 	Type             FieldType
+	TypeParam        string // e.g. the enum name of enum:RewardType, the table name of ref:items
 	IsMultiLineArray bool
 	IsCellArray      bool
+	IsNullable       bool
 	StructFields     []*TableField
 	ParentField      *TableField
 	column           int
@@ -91,7 +112,7 @@ func (f *TableField) structEqual(other *TableField, top bool) bool {
 	if !top && f.Name != other.Name {
 		return false
 	}
-	if f.Type != other.Type || f.IsCellArray != other.IsCellArray {
+	if f.Type != other.Type || f.TypeParam != other.TypeParam || f.IsCellArray != other.IsCellArray || f.IsNullable != other.IsNullable {
 		return false
 	}
 	if len(f.StructFields) != len(other.StructFields) {
@@ -125,8 +146,10 @@ func (f *TableField) Clone() *TableField {
 	clone := &TableField{
 		Name:             f.Name,
 		Type:             f.Type,
+		TypeParam:        f.TypeParam,
 		IsMultiLineArray: f.IsMultiLineArray,
 		IsCellArray:      f.IsCellArray,
+		IsNullable:       f.IsNullable,
 		column:           f.column,
 	}
 	for _, sf := range f.StructFields {
@@ -38,14 +38,44 @@ func (t StructMap) Get(id string) (string, bool) {
 	return "", false
 }
 
+// EnumMap - you can declare an enum with its values separated by |
+//
+//	ex. enum=RewardType:Gold|Gear|Gem
+type EnumMap map[string]string
+
+func (t EnumMap) Values(name string) []string {
+	if t == nil {
+		return nil
+	}
+	if values, ok := t[name]; ok && values != "" {
+		return strings.Split(values, "|")
+	}
+	return nil
+}
+
 type TableMetadata struct {
-	AsMap      bool      `query:"as_map"`
-	SortAscBy  string    `query:"sort_asc_by"`
-	SortDescBy string    `query:"sort_desc_by"`
-	Structs    StructMap `query:"struct"`
+	AsMap  bool `query:"as_map"`
+	AsEnum bool `query:"as_enum"`
+	// ConstraintRow - if true, the row after the description row declares the field constraints
+	ConstraintRow bool      `query:"constraint_row"`
+	SortAscBy     string    `query:"sort_asc_by"`
+	SortDescBy    string    `query:"sort_desc_by"`
+	Structs       StructMap `query:"struct"`
+	Enums         EnumMap   `query:"enum"`
+}
+
+// dataStartRow - the row the data starts from, after the constraint row if it's enabled
+func (m *TableMetadata) dataStartRow() int {
+	if m.ConstraintRow {
+		return TableConstraintRow + 1
+	}
+	return TableDataStartRow
 }
 
 func (m *TableMetadata) Validate(td *TableData) error {
+	if m.AsEnum && FieldType(td.FieldTypes[TableFieldIndexCol]) != FieldTypeString {
+		return fmt.Errorf("as_enum: index field type must be string")
+	}
 	if m.AsMap && (m.SortAscBy != "" || m.SortDescBy != "") {
 		return fmt.Errorf("as_map and sort_by are mutually exclusive")
 	}
@@ -76,6 +106,9 @@ func (m *TableMetadata) validateSortByField(td *TableData, field string) error {
 	if strings.Contains(field, "[]") || strings.Contains(fieldType, "[]") {
 		return fmt.Errorf("sort_by: field is array: %s", field)
 	}
+	if isNullableFieldType(fieldType) {
+		return fmt.Errorf("sort_by: field is nullable: %s", field)
+	}
 	if fieldType == "json" || fieldType == "bool" {
 		return fmt.Errorf("sort_by: invalid field type: %s, %s", field, fieldType)
 	}
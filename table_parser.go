@@ -2,6 +2,7 @@ package nestcsv
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"slices"
@@ -12,11 +13,15 @@ import (
 )
 
 type TableParser struct {
-	td *TableData
+	td        *TableData
+	set       *TableSet
+	omitNull  bool
+	allErrors bool
 }
 
-func NewTableParser(td *TableData) *TableParser {
-	return &TableParser{td: td}
+// NewTableParser - the set is used to resolve enum and ref fields, and can be nil when only parsing fields
+func NewTableParser(td *TableData, set *TableSet) *TableParser {
+	return &TableParser{td: td, set: set}
 }
 
 func (p *TableParser) ParseTableFields(tags []string) ([]*TableField, error) {
@@ -31,12 +36,18 @@ func (p *TableParser) ParseTableFields(tags []string) ([]*TableField, error) {
 		}
 
 		var (
-			nameTokens             = strings.Split(td.FieldNames[col], ".")
-			tokenLen               = len(nameTokens)
-			fieldType, isCellArray = newFieldType(td.FieldTypes[col])
-			multiLineArrayField    *TableField
-			parentField            *TableField
+			nameTokens                        = strings.Split(td.FieldNames[col], ".")
+			tokenLen                          = len(nameTokens)
+			fieldType, typeParam, isCellArray = newFieldType(td.FieldTypes[col])
+			multiLineArrayField               *TableField
+			parentField                       *TableField
 		)
+		if fieldType == FieldTypeEnum && typeParam == "" {
+			return nil, newTableFieldError(td, TableFieldTypeRow, col, fmt.Errorf("enum name is missing"))
+		}
+		if fieldType == FieldTypeRef && typeParam == "" {
+			return nil, newTableFieldError(td, TableFieldTypeRow, col, fmt.Errorf("ref table name is missing"))
+		}
 
 		for i := 0; i < tokenLen; i++ {
 			field := &TableField{
@@ -47,7 +58,7 @@ func (p *TableParser) ParseTableFields(tags []string) ([]*TableField, error) {
 			isMultiLineArray := strings.HasPrefix(field.Name, "[]")
 			if isMultiLineArray {
 				if multiLineArrayField != nil {
-					return nil, fmt.Errorf("nested multi-line array is not allowed: %s, %s", td.Name, field.Name)
+					return nil, newTableFieldError(td, TableFieldNameRow, col, fmt.Errorf("nested multi-line array is not allowed: %s", field.Name))
 				}
 				multiLineArrayField = field
 				field.Name = field.Name[len("[]"):]
@@ -56,7 +67,12 @@ func (p *TableParser) ParseTableFields(tags []string) ([]*TableField, error) {
 
 			if i == tokenLen-1 {
 				field.Type = fieldType
+				field.TypeParam = typeParam
 				field.IsCellArray = isCellArray
+				field.IsNullable = isNullableFieldType(td.FieldTypes[col])
+				if field.IsNullable && field.IsArray() {
+					return nil, newTableFieldError(td, TableFieldTypeRow, col, fmt.Errorf("nullable array is not supported"))
+				}
 			} else {
 				field.Type = FieldTypeStruct
 			}
@@ -67,6 +83,9 @@ func (p *TableParser) ParseTableFields(tags []string) ([]*TableField, error) {
 					return f.Name == field.Name
 				})
 				if existingField != nil {
+					if existingField.IsNullable || field.IsNullable {
+						return nil, newTableFieldError(td, TableFieldTypeRow, col, fmt.Errorf("nullable struct is not supported: %s", field.Name))
+					}
 					field = existingField
 				} else {
 					parentField.StructFields = append(parentField.StructFields, field)
@@ -76,6 +95,9 @@ func (p *TableParser) ParseTableFields(tags []string) ([]*TableField, error) {
 				parentField = findPtr(fields, func(f *TableField) bool {
 					return f.Name == field.Name
 				})
+				if parentField != nil && (parentField.IsNullable || field.IsNullable) {
+					return nil, newTableFieldError(td, TableFieldTypeRow, col, fmt.Errorf("nullable struct is not supported: %s", field.Name))
+				}
 				if parentField == nil {
 					parentField = field
 					fields = append(fields, field)
@@ -105,6 +127,16 @@ func (p *TableParser) Marshal(fields []*TableField) (any, error) {
 			},
 		)
 		multiLineArrayRowCount = make(map[string]int)
+
+		// fail - returns the error, or collects it and continues if all errors should be reported
+		errs = make([]error, 0)
+		fail = func(err error) error {
+			if p.allErrors {
+				errs = append(errs, err)
+				return nil
+			}
+			return err
+		}
 	)
 
 	for rowIdx, row := range td.DataRows {
@@ -112,7 +144,10 @@ func (p *TableParser) Marshal(fields []*TableField) (any, error) {
 		rowContainer, isMultiLineRow := rowMap[id]
 		if isMultiLineRow {
 			if !multiLineArrayExists {
-				return nil, fmt.Errorf("there is no multi-line array field but id is duplicated: %s, %s", td.Name, id)
+				if err := fail(p.cellError(rowIdx, TableFieldIndexCol, id, errors.New("there is no multi-line array field but id is duplicated"))); err != nil {
+					return nil, err
+				}
+				continue
 			}
 		} else {
 			rowContainer = make(map[string]any)
@@ -182,10 +217,13 @@ func (p *TableParser) Marshal(fields []*TableField) (any, error) {
 				}
 				arr := arrayValue.([]any)
 				if len(arr) <= multiLineArrayIdx {
-					cell := row[field.column]
-					v, err := p.parseGoValue(field.Type, cell)
+					cell := p.cell(row, field.column)
+					v, err := p.parseGoValue(field.Type, field.TypeParam, cell)
 					if err != nil {
-						return fmt.Errorf("failed to parse array value: %s, %s, %d, %s, %w", td.Name, field.Name, rowIdx, cell, err)
+						return fail(p.cellError(rowIdx, field.column, cell, fmt.Errorf("failed to parse array value: %w", err)))
+					}
+					if err := p.checkConstraint(field, cell, v); err != nil {
+						return fail(p.cellError(rowIdx, field.column, cell, fmt.Errorf("constraint violation: %w", err)))
 					}
 					arr = append(arr, v)
 				}
@@ -193,28 +231,44 @@ func (p *TableParser) Marshal(fields []*TableField) (any, error) {
 
 			} else if field.IsCellArray {
 				// fill array value
-				cell := row[field.column]
+				cell := p.cell(row, field.column)
 				var arr []any
 				if len(cell) > 0 {
 					cells := strings.Split(cell, ",")
 					for _, elem := range cells {
-						v, err := p.parseGoValue(field.Type, elem)
+						v, err := p.parseGoValue(field.Type, field.TypeParam, elem)
 						if err != nil {
-							return fmt.Errorf("failed to parse array value: %s, %s, %d, %s, %w", td.Name, field.Name, rowIdx, cell, err)
+							return fail(p.cellError(rowIdx, field.column, cell, fmt.Errorf("failed to parse array value: %w", err)))
+						}
+						if err := p.checkConstraint(field, elem, v); err != nil {
+							return fail(p.cellError(rowIdx, field.column, cell, fmt.Errorf("constraint violation: %w", err)))
 						}
 						arr = append(arr, v)
 					}
 				} else {
+					if err := p.checkConstraint(field, cell, nil); err != nil {
+						return fail(p.cellError(rowIdx, field.column, cell, fmt.Errorf("constraint violation: %w", err)))
+					}
 					arr = make([]any, 0)
 				}
 				container[field.Name] = arr
 
 			} else {
 				// fill single value
-				cell := row[field.column]
-				v, err := p.parseGoValue(field.Type, cell)
-				if err != nil {
-					return fmt.Errorf("failed to parse value: %s, %s, %d, %s, %w", td.Name, field.Name, rowIdx, cell, err)
+				cell := p.cell(row, field.column)
+				var v any
+				if !field.IsNullable || cell != "" {
+					var err error
+					v, err = p.parseGoValue(field.Type, field.TypeParam, cell)
+					if err != nil {
+						return fail(p.cellError(rowIdx, field.column, cell, fmt.Errorf("failed to parse value: %w", err)))
+					}
+				}
+				if err := p.checkConstraint(field, cell, v); err != nil {
+					return fail(p.cellError(rowIdx, field.column, cell, fmt.Errorf("constraint violation: %w", err)))
+				}
+				if v == nil && field.IsNullable && p.omitNull {
+					return nil
 				}
 				container[field.Name] = v
 			}
@@ -229,6 +283,10 @@ func (p *TableParser) Marshal(fields []*TableField) (any, error) {
 		}
 	}
 
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
 	if td.Metadata.AsMap {
 		m := make(map[string]any)
 		for id, row := range rowMap {
@@ -246,7 +304,7 @@ func (p *TableParser) Marshal(fields []*TableField) (any, error) {
 	}
 }
 
-func (p *TableParser) parseGoValue(typ FieldType, cell string) (any, error) {
+func (p *TableParser) parseGoValue(typ FieldType, param string, cell string) (any, error) {
 	switch typ {
 	case FieldTypeInt:
 		if cell == "" {
@@ -284,11 +342,63 @@ func (p *TableParser) parseGoValue(typ FieldType, cell string) (any, error) {
 			return nil, fmt.Errorf("failed to unmarshal json: %s, %w", cell, err)
 		}
 		return v, nil
+	case FieldTypeEnum:
+		enum := p.set.Enum(param)
+		if enum == nil {
+			return nil, fmt.Errorf("unknown enum: %s", param)
+		}
+		if cell == "" {
+			// an empty enum cell is likely a mistake, a nullable field or a default= constraint allows it
+			return nil, fmt.Errorf("empty enum value: %s", param)
+		}
+		if !enum.Contains(cell) {
+			return cell, fmt.Errorf("invalid enum value: %s, %s", param, cell)
+		}
+		return cell, nil
+	case FieldTypeRef:
+		refTable := p.set.Table(param)
+		if refTable == nil {
+			return nil, fmt.Errorf("unknown ref table: %s", param)
+		}
+		return p.parseGoValue(refTable.IDFieldType(), "", cell)
 	default:
 		return nil, fmt.Errorf("unknown type: %s", typ)
 	}
 }
 
+func (p *TableParser) cellError(rowIdx, col int, cell string, err error) error {
+	return newTableCellError(p.td, rowIdx, col, cell, err)
+}
+
+// cell - returns the cell of the column, or its default value if the cell is empty
+func (p *TableParser) cell(row []string, col int) string {
+	cell := row[col]
+	if cell == "" && p.td.FieldConstraints != nil {
+		if c := p.td.FieldConstraints[col]; c != nil && c.Default != nil {
+			return *c.Default
+		}
+	}
+	return cell
+}
+
+// checkConstraint - checks the cell and its parsed value, or each element if the field is a cell array
+func (p *TableParser) checkConstraint(field *TableField, cell string, value any) error {
+	if p.td.FieldConstraints == nil {
+		return nil
+	}
+	c := p.td.FieldConstraints[field.column]
+	if c == nil {
+		return nil
+	}
+	if cell == "" {
+		if c.Required {
+			return fmt.Errorf("required")
+		}
+		return nil
+	}
+	return c.Check(cell, value)
+}
+
 func (p *TableParser) checkAllCellsEmpty(field *TableField, row []string) bool {
 	for f := range field.Iterate {
 		if row[f.column] != "" {
@@ -300,10 +410,10 @@ func (p *TableParser) checkAllCellsEmpty(field *TableField, row []string) bool {
 
 func (p *TableParser) sortValues(values []map[string]any, rowIndices []int, field string, desc bool) {
 	fieldCol := slices.Index(p.td.FieldNames, field)
-	fieldType, _ := newFieldType(p.td.FieldTypes[fieldCol])
+	fieldType, typeParam, _ := newFieldType(p.td.FieldTypes[fieldCol])
 	sort.SliceStable(values, func(i, j int) bool {
-		av, _ := p.parseGoValue(fieldType, p.td.DataRows[rowIndices[i]][fieldCol])
-		bv, _ := p.parseGoValue(fieldType, p.td.DataRows[rowIndices[j]][fieldCol])
+		av, _ := p.parseGoValue(fieldType, typeParam, p.cell(p.td.DataRows[rowIndices[i]], fieldCol))
+		bv, _ := p.parseGoValue(fieldType, typeParam, p.cell(p.td.DataRows[rowIndices[j]], fieldCol))
 		compareAsc := p.sortCompareAsc(av, bv)
 		if desc {
 			return !compareAsc
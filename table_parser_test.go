@@ -0,0 +1,277 @@
+package nestcsv
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTableParserEnum(t *testing.T) {
+	tests := []struct {
+		name       string
+		typ        string
+		constraint string
+		cell       string
+		want       any
+		wantErr    string
+	}{
+		{name: "value", typ: "enum:Rarity", cell: "Rare", want: "Rare"},
+		{name: "unknown value", typ: "enum:Rarity", cell: "Epic", wantErr: "invalid enum value: Rarity, Epic"},
+		{name: "empty", typ: "enum:Rarity", cell: "", wantErr: "empty enum value: Rarity"},
+		{name: "empty nullable", typ: "enum:Rarity?", cell: "", want: nil},
+		{name: "empty default", typ: "enum:Rarity", constraint: "default=Common", cell: "", want: "Common"},
+		{name: "unknown enum", typ: "enum:Grade", cell: "A", wantErr: "unknown enum: Grade"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := "enum=Rarity:Common|Rare"
+			rows := [][]string{
+				{metadata, ""},
+				{"all", "all"},
+				{"ID", "Rarity"},
+				{"int", tt.typ},
+				{"", ""},
+			}
+			if tt.constraint != "" {
+				rows[0][0] += "&constraint_row=true"
+				rows = append(rows, []string{"", tt.constraint})
+			}
+			rows = append(rows, []string{"1", tt.cell})
+
+			td, err := ParseTableData("items.csv", "", rows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			set, err := NewTableSet([]*TableData{td})
+			if err != nil {
+				t.Fatal(err)
+			}
+			parser := NewTableParser(td, set)
+			fields, err := parser.ParseTableFields([]string{"all"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			value, err := parser.Marshal(fields)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Marshal() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := value.([]map[string]any)[0]["Rarity"]; got != tt.want {
+				t.Errorf("Rarity = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableParserRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		cell    string
+		want    string // the marshaled value printed by fmt
+		wantErr string // of ValidateRefs
+	}{
+		{name: "id", typ: "ref:items", cell: "1", want: "1"},
+		{name: "string id", typ: "ref:grades", cell: "A", want: "A"},
+		{name: "missing id", typ: "ref:items", cell: "9", want: "9", wantErr: "ref not found: no row 9 in items"},
+		{name: "empty", typ: "ref:items", cell: "", want: "0"},
+		{name: "array", typ: "[]ref:items", cell: "1,2", want: "[1 2]"},
+		{name: "array missing id", typ: "[]ref:items", cell: "1,9", want: "[1 9]", wantErr: "ref not found: no row 9 in items"},
+		{name: "unknown table", typ: "ref:weapons", cell: "1", wantErr: "unknown ref table: weapons"},
+		{name: "id type mismatch", typ: "ref:items", cell: "A", wantErr: `parsing "A"`},
+		{name: "table name missing", typ: "ref", cell: "1", wantErr: "ref table name is missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := ParseTableData("items.csv", "", [][]string{
+				{"", ""}, {"all", "all"}, {"ID", "Name"}, {"int", "string"}, {"", ""},
+				{"1", "sword"},
+				{"2", "shield"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			grades, err := ParseTableData("grades.csv", "", [][]string{
+				{"", ""}, {"all", "all"}, {"ID", "Rate"}, {"string", "int"}, {"", ""},
+				{"A", "1"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			shops, err := ParseTableData("shops.csv", "", [][]string{
+				{"", ""}, {"all", "all"}, {"ID", "Item"}, {"int", tt.typ}, {"", ""},
+				{"1", tt.cell},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			set, err := NewTableSet([]*TableData{items, grades, shops})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the problems of the header or the value are found by both the parser and ValidateRefs
+			parser := NewTableParser(shops, set)
+			var value any
+			fields, err := parser.ParseTableFields([]string{"all"})
+			if err == nil {
+				value, err = parser.Marshal(fields)
+			}
+			if err == nil {
+				err = set.ValidateRefs(false)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != "" {
+				if got := fmt.Sprint(value.([]map[string]any)[0]["Item"]); got != tt.want {
+					t.Errorf("Item = %s, want %s", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTableSetValidateRefsAllErrors(t *testing.T) {
+	items, err := ParseTableData("items.csv", "", [][]string{
+		{"", ""}, {"all", "all"}, {"ID", "Name"}, {"int", "string"}, {"", ""},
+		{"1", "sword"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	shops, err := ParseTableData("shops.csv", "", [][]string{
+		{"", ""}, {"all", "all"}, {"ID", "Items"}, {"int", "[]ref:items"}, {"", ""},
+		{"1", "1,8"},
+		{"2", "9"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewards, err := ParseTableData("rewards.csv", "", [][]string{
+		{"", ""}, {"all", "all"}, {"ID", "Item"}, {"int", "ref:items"}, {"", ""},
+		{"1", "7"},
+		{"2", "x"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := NewTableSet([]*TableData{items, shops, rewards})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := set.ValidateRefs(false); len(CollectTableErrors(err)) != 1 {
+		t.Errorf("ValidateRefs(false) = %v, want the first error only", err)
+	}
+
+	tableErrs := CollectTableErrors(set.ValidateRefs(true))
+	var got []string
+	for _, tableErr := range tableErrs {
+		got = append(got, tableErr.Table+" "+tableErr.Cell+" "+tableErr.Value)
+	}
+	want := []string{"rewards B6 7", "rewards B7 x", "shops B6 1,8", "shops B7 9"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ValidateRefs(true) = %q, want %q", got, want)
+	}
+}
+
+func TestTableParserNullable(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      string
+		cell     string
+		omitNull bool
+		want     any
+		absent   bool // the key is omitted
+	}{
+		{name: "empty", typ: "int", cell: "", want: 0},
+		{name: "empty nullable", typ: "int?", cell: "", want: nil},
+		{name: "zero nullable", typ: "int?", cell: "0", want: 0},
+		{name: "empty nullable string", typ: "string?", cell: "", want: nil},
+		{name: "empty string", typ: "string", cell: "", want: ""},
+		{name: "omit null", typ: "int?", cell: "", omitNull: true, absent: true},
+		{name: "omit null keeps zero", typ: "int?", cell: "0", omitNull: true, want: 0},
+		{name: "omit null keeps non-nullable", typ: "int", cell: "", omitNull: true, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td, err := ParseTableData("items.csv", "", [][]string{
+				{"", ""}, {"all", "all"}, {"ID", "Count"}, {"int", tt.typ}, {"", ""},
+				{"1", tt.cell},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			parser := NewTableParser(td, nil)
+			parser.omitNull = tt.omitNull
+			fields, err := parser.ParseTableFields([]string{"all"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			value, err := parser.Marshal(fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := value.([]map[string]any)[0]["Count"]
+			if ok == tt.absent {
+				t.Fatalf("Count present = %v, want %v", ok, !tt.absent)
+			}
+			if got != tt.want {
+				t.Errorf("Count = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableParserNullableUnsupported(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		types   []string
+		wantErr string
+	}{
+		{name: "cell array", names: []string{"Counts"}, types: []string{"[]int?"}, wantErr: "nullable array is not supported"},
+		{name: "multi-line array", names: []string{"[]Counts"}, types: []string{"int?"}, wantErr: "nullable array is not supported"},
+		{name: "struct", names: []string{"Reward", "Reward.Count"}, types: []string{"int?", "int"}, wantErr: "nullable struct is not supported: Reward"},
+		{name: "struct after fields", names: []string{"Reward.Count", "Reward"}, types: []string{"int", "int?"}, wantErr: "nullable struct is not supported: Reward"},
+		{name: "struct field", names: []string{"Reward.Count", "Reward.Type"}, types: []string{"int?", "string?"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := [][]string{{""}, {"all"}, {"ID"}, {"int"}, {""}, {"1"}}
+			for i := range tt.names {
+				rows[0] = append(rows[0], "")
+				rows[1] = append(rows[1], "all")
+				rows[2] = append(rows[2], tt.names[i])
+				rows[3] = append(rows[3], tt.types[i])
+				rows[4] = append(rows[4], "")
+				rows[5] = append(rows[5], "")
+			}
+			td, err := ParseTableData("items.csv", "", rows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = NewTableParser(td, nil).ParseTableFields([]string{"all"})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseTableFields() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package nestcsv
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TableSet - every table collected from the datasources, with the cross-table information declared by them
+type TableSet struct {
+	Tables []*TableData
+	Enums  map[string]*TableEnum
+	tables map[string]*TableData
+}
+
+func NewTableSet(tableDatas []*TableData) (*TableSet, error) {
+	tables := make([]*TableData, len(tableDatas))
+	copy(tables, tableDatas)
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name < tables[j].Name
+	})
+
+	enums, err := CollectTableEnums(tables)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &TableSet{
+		Tables: tables,
+		Enums:  enums,
+		tables: make(map[string]*TableData, len(tables)),
+	}
+	for _, table := range tables {
+		set.tables[table.Name] = table
+	}
+	return set, nil
+}
+
+func (s *TableSet) Table(name string) *TableData {
+	if s == nil {
+		return nil
+	}
+	return s.tables[name]
+}
+
+func (s *TableSet) Enum(name string) *TableEnum {
+	if s == nil {
+		return nil
+	}
+	return s.Enums[name]
+}
+
+// ValidateRefs - checks that every ref field points to an existing row of the referenced table
+//
+//	If allErrors is true, it checks every cell and returns all the errors joined.
+func (s *TableSet) ValidateRefs(allErrors bool) error {
+	var (
+		ids  = make(map[string]map[any]struct{})
+		errs = make([]error, 0)
+	)
+	for _, td := range s.Tables {
+		for col, typeCell := range td.FieldTypes {
+			fieldType, refName, isCellArray := newFieldType(typeCell)
+			if fieldType != FieldTypeRef {
+				continue
+			}
+			refTable := s.Table(refName)
+			if refTable == nil {
+				return newTableFieldError(td, TableFieldTypeRow, col, fmt.Errorf("unknown ref table: %s", refName))
+			}
+
+			refIDs, ok := ids[refName]
+			if !ok {
+				refIDs = make(map[any]struct{}, len(refTable.DataRows))
+				parser := NewTableParser(refTable, s)
+				for rowIdx, row := range refTable.DataRows {
+					id, err := parser.parseGoValue(refTable.IDFieldType(), "", row[TableFieldIndexCol])
+					if err != nil {
+						return parser.cellError(rowIdx, TableFieldIndexCol, row[TableFieldIndexCol], fmt.Errorf("failed to parse id: %w", err))
+					}
+					refIDs[id] = struct{}{}
+				}
+				ids[refName] = refIDs
+			}
+
+			parser := NewTableParser(td, s)
+			for rowIdx, row := range td.DataRows {
+				cell := parser.cell(row, col)
+				if cell == "" {
+					continue
+				}
+				values := []string{cell}
+				if isCellArray {
+					values = strings.Split(cell, ",")
+				}
+				for _, value := range values {
+					var err error
+					if id, parseErr := parser.parseGoValue(fieldType, refName, value); parseErr != nil {
+						err = fmt.Errorf("failed to parse ref value: %w", parseErr)
+					} else if _, ok := refIDs[id]; !ok {
+						err = fmt.Errorf("ref not found: no row %v in %s", id, refName)
+					}
+					if err != nil {
+						err = parser.cellError(rowIdx, col, cell, err)
+						if !allErrors {
+							return err
+						}
+						errs = append(errs, err)
+					}
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -1,22 +1,62 @@
 package nestcsv
 
-import "gopkg.in/yaml.v3"
+import (
+	"context"
 
+	"gopkg.in/yaml.v3"
+)
+
+// TableWriter - writes a table, creating its files through files
 type TableWriter interface {
-	Write(name string, value any) error
+	Write(ctx context.Context, files *FileSink, name string, value any) error
+}
+
+// TableSchema - the fields written to an output, and the tables they can refer to
+type TableSchema struct {
+	Table  *TableData
+	Fields []*TableField
+	Set    *TableSet
+}
+
+// TableSchemaWriter - a TableWriter encoding the value by the schema instead of its dynamic shape
+type TableSchemaWriter interface {
+	WriteSchema(ctx context.Context, files *FileSink, schema *TableSchema, value any) error
+}
+
+// TableAggregateWriter - a TableWriter collecting every table, Flush is called once after all tables are written
+type TableAggregateWriter interface {
+	Flush(ctx context.Context, files *FileSink) error
 }
 
 type OutputConfig struct {
 	When *When    `yaml:"when,omitempty"`
 	Tags []string `yaml:"tags"`
+	// OmitNull - if true, null values of nullable fields are omitted instead of being written as null
+	OmitNull bool `yaml:"omit_null"`
 
 	exclusiveConfigGroup[TableWriter]
-	JSON *TableWriterJSON `yaml:"json,omitempty"`
-	Bin  *TableWriterBin  `yaml:"bin,omitempty"`
+	JSON     *TableWriterJSON     `yaml:"json,omitempty"`
+	Bin      *TableWriterBin      `yaml:"bin,omitempty"`
+	Msgpack  *TableWriterMsgpack  `yaml:"msgpack,omitempty"`
+	Protobuf *TableWriterProtobuf `yaml:"protobuf,omitempty"`
+	SQL      *TableWriterSQL      `yaml:"sql,omitempty"`
+	SQLite   *TableWriterSQLite   `yaml:"sqlite,omitempty"`
+	Plugin   *TableWriterPlugin   `yaml:"plugin,omitempty"`
+}
+
+// NewOutputConfig - an output of the config built in code, such as a TableWriterMemory or a custom one
+func NewOutputConfig(w TableWriter, tags ...string) OutputConfig {
+	return OutputConfig{Tags: tags, exclusiveConfigGroup: exclusiveConfigGroup[TableWriter]{loaded: w}}
 }
 
-func (c *OutputConfig) Write(tableData *TableData) error {
-	tableParser := NewTableParser(tableData)
+// Write - marshals the table and writes it, allErrors makes the parser report every bad cell instead of the first one
+func (c *OutputConfig) Write(ctx context.Context, files *FileSink, tableData *TableData, set *TableSet, allErrors bool) error {
+	if tableData.Metadata.AsEnum {
+		return nil
+	}
+	tableParser := NewTableParser(tableData, set)
+	tableParser.omitNull = c.OmitNull
+	tableParser.allErrors = allErrors
 	tableFields, err := tableParser.ParseTableFields(c.Tags)
 	if err != nil {
 		return err
@@ -28,7 +68,24 @@ func (c *OutputConfig) Write(tableData *TableData) error {
 	if err != nil {
 		return err
 	}
-	return c.loaded.Write(tableData.Name, value)
+	if writer, ok := c.loaded.(TableSchemaWriter); ok {
+		return writer.WriteSchema(ctx, files, &TableSchema{Table: tableData, Fields: tableFields, Set: set}, value)
+	}
+	return c.loaded.Write(ctx, files, tableData.Name, value)
+}
+
+// IsAggregate - whether the output collects every table, to be written by Flush
+func (c *OutputConfig) IsAggregate() bool {
+	_, ok := c.loaded.(TableAggregateWriter)
+	return ok
+}
+
+// Flush - writes the tables collected by an aggregate writer
+func (c *OutputConfig) Flush(ctx context.Context, files *FileSink) error {
+	if writer, ok := c.loaded.(TableAggregateWriter); ok {
+		return writer.Flush(ctx, files)
+	}
+	return nil
 }
 
 func (c *OutputConfig) UnmarshalYAML(node *yaml.Node) error {
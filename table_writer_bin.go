@@ -1,36 +1,296 @@
 package nestcsv
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The binary table format, the go, ue5 and unity codegens generate the loader of it.
+//
+//	file    : magic "NCSV" | version u8 | schema hash u32le | string count uvarint | strings | row count uvarint | rows
+//	string  : byte length uvarint | utf-8 bytes, every string value is an index of the deduplicated strings
+//	row     : (the key string of a map table) | fields in the column order
+//	int/long: zigzag varint
+//	float   : f64le
+//	bool    : u8
+//	string  : string index uvarint
+//	time    : unix seconds zigzag varint
+//	json    : string index uvarint of the json text
+//	enum    : value index uvarint
+//	ref     : the id type of the referenced table
+//	nullable: u8 presence | value if present
+//	array   : element count uvarint | elements
+//	struct  : fields in the column order
+const (
+	binMagic   = "NCSV"
+	binVersion = 1
 )
 
 type TableWriterBin struct {
-	RootDir string `yaml:"root_dir"`
+	RootDir    string `yaml:"root_dir"`
+	FileSuffix string `yaml:"file_suffix"` // default ".bin", use ".bytes" to load as a TextAsset in Unity
+}
+
+func (e *TableWriterBin) Write(ctx context.Context, files *FileSink, name string, value any) error {
+	return errors.New("bin output requires the table schema")
 }
 
-func (e *TableWriterBin) Write(name string, value any) error {
+func (e *TableWriterBin) WriteSchema(ctx context.Context, files *FileSink, schema *TableSchema, value any) error {
 	if e.RootDir == "" {
 		e.RootDir = "."
 	}
-	jsonBytes, err := json.Marshal(value)
+	if e.FileSuffix == "" {
+		e.FileSuffix = ".bin"
+	}
+
+	data, err := encodeBinTable(schema, value)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to encode the table: %s, %w", schema.Table.Name, err)
 	}
 
-	file, err := createFile(e.RootDir, name, "bin")
+	file, err := files.Create(e.RootDir, schema.Table.Name, e.FileSuffix)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	binHeader := make([]byte, 4)
-	binary.BigEndian.PutUint32(binHeader, uint32(len(jsonBytes)))
-	if _, err := file.Write(binHeader); err != nil {
+	if _, err := file.Write(data); err != nil {
 		return err
 	}
-	if _, err := file.Write(jsonBytes); err != nil {
-		return err
+	return nil
+}
+
+// BinHash - the hash of the binary layout, a loader rejects the file written with a different layout
+func (s *TableSchema) BinHash() uint32 {
+	var layout strings.Builder
+	if s.Table.Metadata.AsMap {
+		layout.WriteString("map:")
+	}
+	var writeFields func(fields []*TableField)
+	writeFields = func(fields []*TableField) {
+		layout.WriteByte('{')
+		for _, field := range fields {
+			layout.WriteString(field.Name)
+			layout.WriteByte(':')
+			if field.IsArray() {
+				layout.WriteString("[]")
+			}
+			switch field.Type {
+			case FieldTypeStruct:
+				writeFields(field.StructFields)
+			case FieldTypeEnum:
+				// an unknown enum or ref table is reported by the code analyzer or the parser
+				if enum := s.Set.Enum(field.TypeParam); enum != nil {
+					layout.WriteString("enum(" + strings.Join(enum.Values, "|") + ")")
+				}
+			case FieldTypeRef:
+				if refTable := s.Set.Table(field.TypeParam); refTable != nil {
+					layout.WriteString(refTable.IDFieldType().String())
+				}
+			default:
+				layout.WriteString(field.Type.String())
+			}
+			if field.IsNullable {
+				layout.WriteByte('?')
+			}
+			layout.WriteByte(',')
+		}
+		layout.WriteByte('}')
+	}
+	writeFields(s.Fields)
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(layout.String()))
+	return h.Sum32()
+}
+
+type binEncoder struct {
+	schema  *TableSchema
+	body    []byte
+	strings []string
+	indices map[string]int
+}
+
+func encodeBinTable(schema *TableSchema, value any) ([]byte, error) {
+	e := &binEncoder{
+		schema:  schema,
+		indices: make(map[string]int),
+	}
+
+	switch rows := value.(type) {
+	case []map[string]any:
+		e.body = binary.AppendUvarint(e.body, uint64(len(rows)))
+		for _, row := range rows {
+			if err := e.writeFields(schema.Fields, row); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(rows))
+		for key := range rows {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		e.body = binary.AppendUvarint(e.body, uint64(len(rows)))
+		for _, key := range keys {
+			e.writeString(key)
+			row, _ := rows[key].(map[string]any)
+			if err := e.writeFields(schema.Fields, row); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unexpected table value: %T", value)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(binMagic)
+	buf.WriteByte(binVersion)
+	buf.Write(binary.LittleEndian.AppendUint32(nil, schema.BinHash()))
+	buf.Write(binary.AppendUvarint(nil, uint64(len(e.strings))))
+	for _, s := range e.strings {
+		buf.Write(binary.AppendUvarint(nil, uint64(len(s))))
+		buf.WriteString(s)
+	}
+	buf.Write(e.body)
+	return buf.Bytes(), nil
+}
+
+// writeFields - writes the fields of a struct, a missing field is written as its zero value
+func (e *binEncoder) writeFields(fields []*TableField, container map[string]any) error {
+	for _, field := range fields {
+		if err := e.writeField(field, container[field.Name]); err != nil {
+			return fmt.Errorf("%s: %w", field.Name, err)
+		}
 	}
 	return nil
 }
+
+func (e *binEncoder) writeField(field *TableField, value any) error {
+	if field.IsArray() {
+		var elems []any
+		switch v := value.(type) {
+		case []any:
+			elems = v
+		case []map[string]any:
+			elems = make([]any, len(v))
+			for i, elem := range v {
+				elems[i] = elem
+			}
+		case nil:
+		default:
+			return fmt.Errorf("unexpected array value: %T", value)
+		}
+		e.body = binary.AppendUvarint(e.body, uint64(len(elems)))
+		for _, elem := range elems {
+			if err := e.writeElem(field, elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if field.IsNullable {
+		if value == nil {
+			e.body = append(e.body, 0)
+			return nil
+		}
+		e.body = append(e.body, 1)
+	}
+	return e.writeElem(field, value)
+}
+
+func (e *binEncoder) writeElem(field *TableField, value any) error {
+	switch field.Type {
+	case FieldTypeStruct:
+		container, _ := value.(map[string]any)
+		return e.writeFields(field.StructFields, container)
+
+	case FieldTypeEnum:
+		s, _ := value.(string)
+		enum := e.schema.Set.Enum(field.TypeParam)
+		idx := slices.Index(enum.Values, s)
+		if idx < 0 {
+			if s != "" {
+				return fmt.Errorf("invalid enum value: %s, %s", field.TypeParam, s)
+			}
+			idx = 0
+		}
+		e.body = binary.AppendUvarint(e.body, uint64(idx))
+		return nil
+
+	case FieldTypeRef:
+		return e.writeValue(e.schema.Set.Table(field.TypeParam).IDFieldType(), value)
+
+	default:
+		return e.writeValue(field.Type, value)
+	}
+}
+
+func (e *binEncoder) writeValue(typ FieldType, value any) error {
+	switch typ {
+	case FieldTypeInt, FieldTypeLong:
+		var n int64
+		switch v := value.(type) {
+		case int:
+			n = int64(v)
+		case int64:
+			n = v
+		case nil:
+		default:
+			return fmt.Errorf("unexpected integer value: %T", value)
+		}
+		e.body = binary.AppendVarint(e.body, n)
+
+	case FieldTypeFloat:
+		f, _ := value.(float64)
+		e.body = binary.LittleEndian.AppendUint64(e.body, math.Float64bits(f))
+
+	case FieldTypeBool:
+		if b, _ := value.(bool); b {
+			e.body = append(e.body, 1)
+		} else {
+			e.body = append(e.body, 0)
+		}
+
+	case FieldTypeString:
+		s, _ := value.(string)
+		e.writeString(s)
+
+	case FieldTypeTime:
+		t, _ := value.(time.Time)
+		e.body = binary.AppendVarint(e.body, t.Unix())
+
+	case FieldTypeJSON:
+		jsonBytes, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		e.writeString(string(jsonBytes))
+
+	default:
+		return fmt.Errorf("unsupported type: %s", typ)
+	}
+	return nil
+}
+
+func (e *binEncoder) writeString(s string) {
+	idx, ok := e.indices[s]
+	if !ok {
+		idx = len(e.strings)
+		e.strings = append(e.strings, s)
+		e.indices[s] = idx
+	}
+	e.body = binary.AppendUvarint(e.body, uint64(idx))
+}
@@ -0,0 +1,73 @@
+package nestcsv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestEncodeBinTable(t *testing.T) {
+	rows := [][]string{
+		{""},
+		{"all", "all", "all"},
+		{"ID", "Name", "Count"},
+		{"int", "string", "int"},
+		{"", "", ""},
+		{"1", "a", "-1"},
+		{"2", "a", "64"},
+	}
+	td, err := ParseTableData("items.csv", "", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := NewTableParser(td, nil)
+	fields, err := parser.ParseTableFields([]string{"all"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := parser.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := &TableSchema{Table: td, Fields: fields}
+	data, err := encodeBinTable(schema, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte(binMagic)
+	want = append(want, binVersion)
+	want = binary.LittleEndian.AppendUint32(want, schema.BinHash())
+	want = append(want,
+		1, 1, 'a', // the deduplicated strings
+		2,       // rows
+		2, 0, 1, // 1, "a", -1
+		4, 0, 0x80, 0x01, // 2, "a", 64
+	)
+	if !bytes.Equal(data, want) {
+		t.Errorf("unexpected bytes:\n got %v\nwant %v", data, want)
+	}
+}
+
+func TestBinHashUnknownType(t *testing.T) {
+	for typ, wantErr := range map[string]string{
+		"enum:Grade": "unknown enum: Grade",
+		"ref:shops":  "unknown ref table: shops",
+	} {
+		td, err := ParseTableData("items.csv", "", [][]string{
+			{""}, {"all", "all"}, {"ID", "Value"}, {"int", typ}, {"", ""}, {"1", "a"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		set, err := NewTableSet([]*TableData{td})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the hash is taken before the code analyzer reports the type
+		if _, err := AnalyzeTableCode(set, []string{"all"}); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("AnalyzeTableCode() error = %v, want %q", err, wantErr)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package nestcsv
 
 import (
+	"context"
 	"encoding/json"
 )
 
@@ -9,7 +10,7 @@ type TableWriterJSON struct {
 	Indent  string `yaml:"indent"`
 }
 
-func (e *TableWriterJSON) Write(name string, value any) error {
+func (e *TableWriterJSON) Write(ctx context.Context, files *FileSink, name string, value any) error {
 	if e.RootDir == "" {
 		e.RootDir = "."
 	}
@@ -26,7 +27,7 @@ func (e *TableWriterJSON) Write(name string, value any) error {
 		return err
 	}
 
-	file, err := createFile(e.RootDir, name, "json")
+	file, err := files.Create(e.RootDir, name, "json")
 	if err != nil {
 		return err
 	}
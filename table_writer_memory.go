@@ -0,0 +1,31 @@
+package nestcsv
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// TableWriterMemory - keeps the marshaled value of every table, to run nestcsv as a library. It's not in the yaml
+// config, use NewOutputConfig
+type TableWriterMemory struct {
+	mu     sync.Mutex
+	values map[string]any
+}
+
+func (e *TableWriterMemory) Write(ctx context.Context, files *FileSink, name string, value any) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.values == nil {
+		e.values = make(map[string]any)
+	}
+	e.values[name] = value
+	return nil
+}
+
+// Values - the value of every table written so far, by the table name
+func (e *TableWriterMemory) Values() map[string]any {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return maps.Clone(e.values)
+}
@@ -0,0 +1,166 @@
+package nestcsv
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// msgpackTimestampExt - the extension type of the msgpack timestamp
+const msgpackTimestampExt = 0xff
+
+type TableWriterMsgpack struct {
+	RootDir string `yaml:"root_dir"`
+}
+
+func (e *TableWriterMsgpack) Write(ctx context.Context, files *FileSink, name string, value any) error {
+	if e.RootDir == "" {
+		e.RootDir = "."
+	}
+
+	data, err := appendMsgpack(nil, value)
+	if err != nil {
+		return fmt.Errorf("failed to encode the table: %s, %w", name, err)
+	}
+
+	file, err := files.Create(e.RootDir, name, "msgpack")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+	return nil
+}
+
+// appendMsgpack - encodes a value produced by TableParser.Marshal, the map keys are sorted to keep the output stable
+func appendMsgpack(b []byte, value any) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return append(b, 0xc0), nil
+	case *any:
+		if v == nil {
+			return append(b, 0xc0), nil
+		}
+		return appendMsgpack(b, *v)
+	case bool:
+		if v {
+			return append(b, 0xc3), nil
+		}
+		return append(b, 0xc2), nil
+	case int:
+		return appendMsgpackInt(b, int64(v)), nil
+	case int64:
+		return appendMsgpackInt(b, v), nil
+	case float64:
+		b = append(b, 0xcb)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(v)), nil
+	case string:
+		return appendMsgpackString(b, v), nil
+	case time.Time:
+		return appendMsgpackTime(b, v), nil
+
+	case []any:
+		b = appendMsgpackHeader(b, len(v), 0x90, 0xdc)
+		for _, elem := range v {
+			var err error
+			if b, err = appendMsgpack(b, elem); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case []map[string]any:
+		b = appendMsgpackHeader(b, len(v), 0x90, 0xdc)
+		for _, elem := range v {
+			var err error
+			if b, err = appendMsgpack(b, elem); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		b = appendMsgpackHeader(b, len(v), 0x80, 0xde)
+		for _, key := range keys {
+			b = appendMsgpackString(b, key)
+			var err error
+			if b, err = appendMsgpack(b, v[key]); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		return b, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected value: %T", value)
+	}
+}
+
+func appendMsgpackInt(b []byte, n int64) []byte {
+	switch {
+	case n >= 0 && n <= math.MaxInt8:
+		return append(b, byte(n))
+	case n < 0 && n >= -32:
+		return append(b, byte(n))
+	case n >= math.MinInt8 && n <= math.MaxInt8:
+		return append(b, 0xd0, byte(n))
+	case n >= math.MinInt16 && n <= math.MaxInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(n))
+	case n >= math.MinInt32 && n <= math.MaxInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(n))
+	}
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+// appendMsgpackHeader - the header of an array (fix 0x90, 0xdc) or a map (fix 0x80, 0xde)
+func appendMsgpackHeader(b []byte, n int, fix, code16 byte) []byte {
+	switch {
+	case n < 16:
+		return append(b, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, code16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, code16+1), uint32(n))
+	}
+}
+
+// appendMsgpackTime - the timestamp extension in the smallest of the 32, 64 and 96 bit formats
+func appendMsgpackTime(b []byte, t time.Time) []byte {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	switch {
+	case sec >= 0 && sec <= math.MaxUint32 && nsec == 0:
+		b = append(b, 0xd6, msgpackTimestampExt)
+		return binary.BigEndian.AppendUint32(b, uint32(sec))
+	case sec >= 0 && sec < 1<<34:
+		b = append(b, 0xd7, msgpackTimestampExt)
+		return binary.BigEndian.AppendUint64(b, nsec<<34|uint64(sec))
+	default:
+		b = append(b, 0xc7, 12, msgpackTimestampExt)
+		b = binary.BigEndian.AppendUint32(b, uint32(nsec))
+		return binary.BigEndian.AppendUint64(b, uint64(sec))
+	}
+}
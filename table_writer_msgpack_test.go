@@ -0,0 +1,36 @@
+package nestcsv
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestAppendMsgpack(t *testing.T) {
+	jsonValue := any([]any{true, nil})
+	cases := []struct {
+		value any
+		want  []byte
+	}{
+		{1, []byte{0x01}},
+		{-1, []byte{0xff}},
+		{-100, []byte{0xd0, 0x9c}},
+		{int64(1 << 40), []byte{0xd3, 0, 0, 1, 0, 0, 0, 0, 0}},
+		{1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"hi", []byte{0xa2, 'h', 'i'}},
+		{&jsonValue, []byte{0x92, 0xc3, 0xc0}},
+		{time.Unix(1, 0), []byte{0xd6, 0xff, 0, 0, 0, 1}},
+		{time.Unix(1, 1), []byte{0xd7, 0xff, 0, 0, 0, 0x04, 0, 0, 0, 1}},
+		{time.Time{}, []byte{0xc7, 12, 0xff, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xf1, 0x88, 0x6e, 0x09, 0}},
+		{map[string]any{"b": 2, "a": []map[string]any{{}}}, []byte{0x82, 0xa1, 'a', 0x91, 0x80, 0xa1, 'b', 0x02}},
+	}
+	for _, c := range cases {
+		got, err := appendMsgpack(nil, c.value)
+		if err != nil {
+			t.Fatalf("%v: %v", c.value, err)
+		}
+		if !bytes.Equal(got, c.want) {
+			t.Errorf("%v: got % x, want % x", c.value, got, c.want)
+		}
+	}
+}
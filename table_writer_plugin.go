@@ -0,0 +1,29 @@
+package nestcsv
+
+import "context"
+
+// TableWriterPlugin - writes the files a plugin encodes from each table
+//
+//	The request is {"kind": "output", "options": {...}, "name": "items", "value": [...]}, where the value is
+//	the table as the json output writes it, and the response is
+//	{"files": [{"path": "items.dat", "content": "..."}]}, or "data" with the base64 encoded content of a binary file.
+//	The paths are relative to the root directory.
+type TableWriterPlugin struct {
+	RootDir string `yaml:"root_dir"`
+	Plugin  `yaml:",inline"`
+}
+
+type pluginFilesResponse struct {
+	Files []PluginFile `json:"files"`
+}
+
+func (e *TableWriterPlugin) Write(ctx context.Context, files *FileSink, name string, value any) error {
+	if e.RootDir == "" {
+		e.RootDir = "."
+	}
+	var response pluginFilesResponse
+	if err := e.call(ctx, "output", map[string]any{"name": name, "value": value}, &response); err != nil {
+		return err
+	}
+	return writePluginFiles(files, e.RootDir, response.Files)
+}
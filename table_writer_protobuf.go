@@ -0,0 +1,343 @@
+package nestcsv
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	protobufVarint = 0
+	protobufI64    = 1
+	protobufLen    = 2
+)
+
+// TableWriterProtobuf - writes a table as the serialized <Table>Table message of the protobuf codegen
+//
+//	The field numbers are taken from the lock file shared with the codegen,
+//	so both must use the same lock file and the same tags.
+type TableWriterProtobuf struct {
+	RootDir  string `yaml:"root_dir"`
+	LockFile string `yaml:"lock_file"` // default "protobuf.lock.yaml"
+}
+
+func (e *TableWriterProtobuf) Write(ctx context.Context, files *FileSink, name string, value any) error {
+	return errors.New("protobuf output requires the table schema")
+}
+
+func (e *TableWriterProtobuf) WriteSchema(ctx context.Context, files *FileSink, schema *TableSchema, value any) error {
+	if e.RootDir == "" {
+		e.RootDir = "."
+	}
+	if e.LockFile == "" {
+		e.LockFile = defaultProtobufLockFile
+	}
+
+	file, err := analyzeTableSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	err = withProtobufLock(files, e.LockFile, func(lock *protobufLock) error {
+		lock.assignFile(file)
+		enc := &protobufEncoder{lock: lock}
+		data, err = enc.appendTable(nil, file, value)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode the table: %s, %w", schema.Table.Name, err)
+	}
+
+	out, err := files.Create(e.RootDir, schema.Table.Name, "pb")
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := out.Write(data); err != nil {
+		return err
+	}
+	return nil
+}
+
+type protobufEncoder struct {
+	lock *protobufLock
+}
+
+// appendTable - the rows field (1) of the table message, a map table is written as the map entries
+func (e *protobufEncoder) appendTable(b []byte, file *CodeFile, value any) ([]byte, error) {
+	switch rows := value.(type) {
+	case []map[string]any:
+		for _, row := range rows {
+			msg, err := e.appendStruct(nil, file.Struct, row)
+			if err != nil {
+				return nil, err
+			}
+			b = protobufAppendLen(b, 1, msg)
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(rows))
+		for key := range rows {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			var entry []byte
+			switch file.IDFieldType {
+			case FieldTypeInt, FieldTypeLong:
+				n, err := strconv.ParseInt(key, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid map key: %s, %w", key, err)
+				}
+				entry = protobufAppendVarint(entry, 1, uint64(n))
+			default:
+				entry = protobufAppendLen(entry, 1, []byte(key))
+			}
+			row, _ := rows[key].(map[string]any)
+			msg, err := e.appendStruct(nil, file.Struct, row)
+			if err != nil {
+				return nil, err
+			}
+			entry = protobufAppendLen(entry, 2, msg)
+			b = protobufAppendLen(b, 1, entry)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected table value: %T", value)
+	}
+	return b, nil
+}
+
+func (e *protobufEncoder) appendStruct(b []byte, s *CodeStruct, container map[string]any) ([]byte, error) {
+	for _, field := range s.Fields {
+		var err error
+		b, err = e.appendField(b, e.lock.fieldNumber(s, field), field, container[field.Name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.Name, err)
+		}
+	}
+	return b, nil
+}
+
+// appendField - writes a field as proto3 does, the zero value of a non-optional scalar is omitted
+func (e *protobufEncoder) appendField(b []byte, num int, field *CodeStructField, value any) ([]byte, error) {
+	if field.IsArray {
+		var elems []any
+		switch v := value.(type) {
+		case []any:
+			elems = v
+		case []map[string]any:
+			elems = make([]any, len(v))
+			for i, elem := range v {
+				elems[i] = elem
+			}
+		case nil:
+		default:
+			return nil, fmt.Errorf("unexpected array value: %T", value)
+		}
+		if len(elems) == 0 {
+			return b, nil
+		}
+
+		if in(field.Type, FieldTypeInt, FieldTypeLong, FieldTypeFloat, FieldTypeBool, FieldTypeEnum) {
+			// packed
+			var packed []byte
+			for _, elem := range elems {
+				var err error
+				if packed, _, err = e.appendScalar(packed, field, elem); err != nil {
+					return nil, err
+				}
+			}
+			return protobufAppendLen(b, num, packed), nil
+		}
+		for _, elem := range elems {
+			var err error
+			if b, err = e.appendElem(b, num, field, elem, true); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	}
+
+	if field.IsNullable && value == nil {
+		return b, nil
+	}
+	return e.appendElem(b, num, field, value, field.IsNullable)
+}
+
+// appendElem - writes a single value, keepZero writes the zero value of a scalar too
+func (e *protobufEncoder) appendElem(b []byte, num int, field *CodeStructField, value any, keepZero bool) ([]byte, error) {
+	switch field.Type {
+	case FieldTypeStruct:
+		container, _ := value.(map[string]any)
+		msg, err := e.appendStruct(nil, field.StructRef, container)
+		if err != nil {
+			return nil, err
+		}
+		return protobufAppendLen(b, num, msg), nil
+
+	case FieldTypeTime:
+		t, _ := value.(time.Time)
+		return protobufAppendLen(b, num, protobufAppendTimestamp(nil, t)), nil
+
+	case FieldTypeJSON:
+		if v, ok := value.(*any); ok && v != nil {
+			value = *v
+		}
+		msg, err := protobufAppendValue(nil, value)
+		if err != nil {
+			return nil, err
+		}
+		return protobufAppendLen(b, num, msg), nil
+
+	case FieldTypeString:
+		s, _ := value.(string)
+		if s == "" && !keepZero {
+			return b, nil
+		}
+		return protobufAppendLen(b, num, []byte(s)), nil
+
+	default:
+		scalar, wireType, err := e.appendScalar(nil, field, value)
+		if err != nil {
+			return nil, err
+		}
+		if !keepZero && protobufIsZero(scalar) {
+			return b, nil
+		}
+		b = protobufAppendTag(b, num, wireType)
+		return append(b, scalar...), nil
+	}
+}
+
+// appendScalar - writes a numeric, bool or enum value without the tag
+func (e *protobufEncoder) appendScalar(b []byte, field *CodeStructField, value any) ([]byte, int, error) {
+	switch field.Type {
+	case FieldTypeInt, FieldTypeLong:
+		var n int64
+		switch v := value.(type) {
+		case int:
+			n = int64(v)
+		case int64:
+			n = v
+		case nil:
+		default:
+			return nil, 0, fmt.Errorf("unexpected integer value: %T", value)
+		}
+		return binary.AppendUvarint(b, uint64(n)), protobufVarint, nil
+
+	case FieldTypeFloat:
+		f, _ := value.(float64)
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(f)), protobufI64, nil
+
+	case FieldTypeBool:
+		if v, _ := value.(bool); v {
+			return append(b, 1), protobufVarint, nil
+		}
+		return append(b, 0), protobufVarint, nil
+
+	case FieldTypeEnum:
+		s, _ := value.(string)
+		if s == "" {
+			s = field.EnumRef.Values[0]
+		}
+		if !has(field.EnumRef.Values, s) {
+			return nil, 0, fmt.Errorf("invalid enum value: %s, %s", field.EnumRef.Name, s)
+		}
+		return binary.AppendUvarint(b, uint64(e.lock.enumNumber(field.EnumRef, s))), protobufVarint, nil
+
+	default:
+		return nil, 0, fmt.Errorf("unsupported type: %s", field.Type)
+	}
+}
+
+// protobufAppendValue - writes a json value as google.protobuf.Value
+func protobufAppendValue(b []byte, value any) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return protobufAppendVarint(b, 1, 0), nil
+	case float64:
+		b = protobufAppendTag(b, 2, protobufI64)
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(v)), nil
+	case string:
+		return protobufAppendLen(b, 3, []byte(v)), nil
+	case bool:
+		if v {
+			return protobufAppendVarint(b, 4, 1), nil
+		}
+		return protobufAppendVarint(b, 4, 0), nil
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		// google.protobuf.Struct: map<string, Value> fields = 1
+		var fields []byte
+		for _, key := range keys {
+			elem, err := protobufAppendValue(nil, v[key])
+			if err != nil {
+				return nil, err
+			}
+			entry := protobufAppendLen(nil, 1, []byte(key))
+			entry = protobufAppendLen(entry, 2, elem)
+			fields = protobufAppendLen(fields, 1, entry)
+		}
+		return protobufAppendLen(b, 5, fields), nil
+	case []any:
+		// google.protobuf.ListValue: repeated Value values = 1
+		var values []byte
+		for _, elem := range v {
+			msg, err := protobufAppendValue(nil, elem)
+			if err != nil {
+				return nil, err
+			}
+			values = protobufAppendLen(values, 1, msg)
+		}
+		return protobufAppendLen(b, 6, values), nil
+	default:
+		return nil, fmt.Errorf("unexpected json value: %T", value)
+	}
+}
+
+// protobufAppendTimestamp - writes google.protobuf.Timestamp
+func protobufAppendTimestamp(b []byte, t time.Time) []byte {
+	if sec := t.Unix(); sec != 0 {
+		b = protobufAppendVarint(b, 1, uint64(sec))
+	}
+	if nsec := t.Nanosecond(); nsec != 0 {
+		b = protobufAppendVarint(b, 2, uint64(nsec))
+	}
+	return b
+}
+
+func protobufAppendTag(b []byte, num, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(num)<<3|uint64(wireType))
+}
+
+func protobufAppendVarint(b []byte, num int, v uint64) []byte {
+	b = protobufAppendTag(b, num, protobufVarint)
+	return binary.AppendUvarint(b, v)
+}
+
+func protobufAppendLen(b []byte, num int, v []byte) []byte {
+	b = protobufAppendTag(b, num, protobufLen)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func protobufIsZero(scalar []byte) bool {
+	for _, c := range scalar {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,276 @@
+package nestcsv
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sqlInsertBatchSize - the rows of an INSERT statement, keeps a statement under the packet limit of mysql
+const sqlInsertBatchSize = 100
+
+// TableWriterSQL - writes the rows of every table as one sql dump for the tables of the sql_ddl codegen
+//
+//	The statements of every table and its child tables are written into one file in a transaction,
+//	so the output must use the same tags as the codegen.
+type TableWriterSQL struct {
+	RootDir  string `yaml:"root_dir"`
+	FileName string `yaml:"file_name"` // default "data.sql"
+	Dialect  string `yaml:"dialect"`   // postgres (default), mysql or sqlite
+	// Copy - writes COPY ... FROM stdin instead of INSERT, postgres only, the file must be run by psql
+	Copy bool `yaml:"copy"`
+	// Truncate - empties every table before inserting, in the transaction of the inserts
+	Truncate bool `yaml:"truncate"`
+
+	mu     sync.Mutex
+	tables map[string]*sqlTableRows
+}
+
+// sqlTableRows - the sql tables of a table, the table and its child tables, and their rows
+type sqlTableRows struct {
+	tables []*sqlTable
+	rows   sqlRows
+}
+
+func newSQLTableRows(schema *TableSchema, value any) (*sqlTableRows, error) {
+	file, err := analyzeTableSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	tables := newSQLTables(file)
+	rows, err := newSQLRows(tables[0], value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the table: %s, %w", schema.Table.Name, err)
+	}
+	return &sqlTableRows{tables: tables, rows: rows}, nil
+}
+
+func (e *TableWriterSQL) Write(ctx context.Context, files *FileSink, name string, value any) error {
+	return errors.New("sql output requires the table schema")
+}
+
+// WriteSchema - collects the rows of a table, they are written by Flush
+func (e *TableWriterSQL) WriteSchema(ctx context.Context, files *FileSink, schema *TableSchema, value any) error {
+	table, err := newSQLTableRows(schema, value)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.tables == nil {
+		e.tables = make(map[string]*sqlTableRows)
+	}
+	e.tables[schema.Table.Name] = table
+	return nil
+}
+
+// Flush - writes the dump of the collected tables, the parent tables are emptied after their children
+// and filled before them, as the child tables refer to their parents
+func (e *TableWriterSQL) Flush(ctx context.Context, files *FileSink) error {
+	e.mu.Lock()
+	collected := e.tables
+	e.tables = nil
+	e.mu.Unlock()
+	if len(collected) == 0 {
+		return nil
+	}
+
+	if e.RootDir == "" {
+		e.RootDir = "."
+	}
+	if e.FileName == "" {
+		e.FileName = "data.sql"
+	}
+	dialect, err := newSQLDialect(e.Dialect)
+	if err != nil {
+		return err
+	}
+	if e.Copy && dialect != sqlDialectPostgres {
+		return fmt.Errorf("copy is not supported by the sql dialect: %s", dialect)
+	}
+
+	var (
+		tables = make([]*sqlTable, 0, len(collected))
+		rows   = make(sqlRows)
+	)
+	for _, name := range slices.Sorted(maps.Keys(collected)) {
+		tables = append(tables, collected[name].tables...)
+		maps.Copy(rows, collected[name].rows)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("-- Code generated by \"nestcsv\"; DO NOT EDIT.\n")
+	dialect.writeBegin(&b)
+	if e.Truncate {
+		dialect.writeTruncate(&b, tables)
+	}
+	for _, table := range tables {
+		if e.Copy {
+			err = dialect.writeCopy(&b, table, rows[table])
+		} else {
+			err = dialect.writeInsert(&b, table, rows[table])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to encode the table: %s, %w", table.Name, err)
+		}
+	}
+	b.WriteString("\nCOMMIT;\n")
+
+	out, err := files.Create(e.RootDir, e.FileName, cmp.Or(filepath.Ext(e.FileName), ".sql"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := out.Write(b.Bytes()); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d sqlDialect) writeBegin(b *bytes.Buffer) {
+	if d == sqlDialectMySQL {
+		b.WriteString("\nSTART TRANSACTION;\n")
+		return
+	}
+	b.WriteString("\nBEGIN;\n")
+}
+
+// writeTruncate - TRUNCATE of mysql commits implicitly and fails on a table referred by a foreign key,
+// and sqlite has no TRUNCATE, so the rows are deleted from the children first instead
+func (d sqlDialect) writeTruncate(b *bytes.Buffer, tables []*sqlTable) {
+	if d == sqlDialectPostgres {
+		names := make([]string, len(tables))
+		for i, table := range tables {
+			names[i] = d.quote(table.Name)
+		}
+		fmt.Fprintf(b, "TRUNCATE TABLE %s;\n", strings.Join(names, ", "))
+		return
+	}
+	for _, table := range slices.Backward(tables) {
+		fmt.Fprintf(b, "DELETE FROM %s;\n", d.quote(table.Name))
+	}
+}
+
+func (d sqlDialect) writeInsert(b *bytes.Buffer, table *sqlTable, rows [][]any) error {
+	for batch := range slices.Chunk(rows, sqlInsertBatchSize) {
+		fmt.Fprintf(b, "\nINSERT INTO %s (%s) VALUES", d.quote(table.Name), d.quoteList(table.Columns))
+		for i, row := range batch {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString("\n  (")
+			for j, column := range table.Columns {
+				if j > 0 {
+					b.WriteString(", ")
+				}
+				literal, err := d.literal(column, row[j])
+				if err != nil {
+					return fmt.Errorf("%s: %w", column.Name, err)
+				}
+				b.WriteString(literal)
+			}
+			b.WriteByte(')')
+		}
+		b.WriteString(";\n")
+	}
+	return nil
+}
+
+// writeCopy - the text format of COPY, a null is \N and the backslashes and the control characters are escaped
+func (d sqlDialect) writeCopy(b *bytes.Buffer, table *sqlTable, rows [][]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	fmt.Fprintf(b, "\nCOPY %s (%s) FROM stdin;\n", d.quote(table.Name), d.quoteList(table.Columns))
+	for _, row := range rows {
+		for j, column := range table.Columns {
+			if j > 0 {
+				b.WriteByte('\t')
+			}
+			if row[j] == nil {
+				b.WriteString(`\N`)
+				continue
+			}
+			text, _, err := sqlText(column, row[j])
+			if err != nil {
+				return fmt.Errorf("%s: %w", column.Name, err)
+			}
+			b.WriteString(sqlCopyReplacer.Replace(text))
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString("\\.\n")
+	return nil
+}
+
+var (
+	sqlCopyReplacer  = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+	sqlMySQLReplacer = strings.NewReplacer(`\`, `\\`, "'", "''", "\x00", `\0`, "\x1a", `\Z`)
+)
+
+// literal - a mysql string escapes the backslashes too, unless NO_BACKSLASH_ESCAPES is set
+func (d sqlDialect) literal(column *sqlColumn, value any) (string, error) {
+	if value == nil {
+		return "NULL", nil
+	}
+	if b, ok := value.(bool); ok {
+		return strings.ToUpper(strconv.FormatBool(b)), nil
+	}
+	text, quoted, err := sqlText(column, value)
+	if err != nil {
+		return "", err
+	}
+	if !quoted {
+		return text, nil
+	}
+	if d == sqlDialectMySQL {
+		return "'" + sqlMySQLReplacer.Replace(text) + "'", nil
+	}
+	return sqlString(text), nil
+}
+
+// sqlText - the text of a value, and whether it's quoted as a literal
+func sqlText(column *sqlColumn, value any) (string, bool, error) {
+	if column.IsJSON {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", false, err
+		}
+		return string(data), true, nil
+	}
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "t", false, nil
+		}
+		return "f", false, nil
+	case int:
+		return strconv.Itoa(v), false, nil
+	case int64:
+		return strconv.FormatInt(v, 10), false, nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", false, fmt.Errorf("unsupported float value: %v", v)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), false, nil
+	case string:
+		return v, true, nil
+	case time.Time:
+		return v.UTC().Format(sqlTimeLayout), true, nil
+	default:
+		return "", false, fmt.Errorf("unexpected value: %T", value)
+	}
+}
@@ -0,0 +1,106 @@
+package nestcsv
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSQLLiteral(t *testing.T) {
+	str := &sqlColumn{Type: FieldTypeString}
+	tests := []struct {
+		dialect sqlDialect
+		column  *sqlColumn
+		value   any
+		want    string
+	}{
+		{sqlDialectPostgres, str, `it's a \ test`, `'it''s a \ test'`},
+		{sqlDialectMySQL, str, `it's a \ test`, `'it''s a \\ test'`},
+		{sqlDialectPostgres, str, nil, "NULL"},
+		{sqlDialectPostgres, &sqlColumn{Type: FieldTypeBool}, true, "TRUE"},
+		{sqlDialectPostgres, &sqlColumn{Type: FieldTypeFloat}, 0.5, "0.5"},
+		{sqlDialectPostgres, &sqlColumn{Type: FieldTypeTime}, time.Date(2024, 9, 30, 11, 0, 0, 0, time.UTC), "'2024-09-30 11:00:00'"},
+		{sqlDialectMySQL, &sqlColumn{Type: FieldTypeString, IsJSON: true}, []any{`a"b`}, `'["a\\"b"]'`},
+	}
+	for _, tt := range tests {
+		got, err := tt.dialect.literal(tt.column, tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("unexpected %s literal of %v: %s, want %s", tt.dialect, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestTableWriterSQLTruncate(t *testing.T) {
+	rootDir := t.TempDir()
+	config := &Config{
+		Datasources: []DatasourceConfig{NewDatasourceConfig(&DatasourceMemory{Tables: []MemoryTable{
+			{File: "items.csv", Rows: [][]string{
+				{""}, {"all", "all", "all"}, {"ID", "[]Rewards.Type", "[]Rewards.Count"}, {"int", "string", "int"}, {""},
+				{"1", "gold", "10"}, {"1", "gem", "1"}, {"2", "gold", "5"},
+			}},
+			{File: "shops.csv", Rows: [][]string{{""}, {"all", "all"}, {"ID", "Name"}, {"int", "string"}, {""}, {"1", "main"}}},
+		}})},
+		Outputs:  []OutputConfig{NewOutputConfig(&TableWriterSQL{RootDir: rootDir, Dialect: "sqlite", Truncate: true}, "all")},
+		Codegens: []CodegenConfig{NewCodegenConfig(&CodegenSQLDDL{RootDir: rootDir, Dialect: "sqlite"}, "all")},
+	}
+	result, err := Run(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump := string(result.Files[filepath.Join(rootDir, "data.sql")])
+	if len(result.Files) != 2 {
+		t.Fatalf("files = %d, want the dump and the schema", len(result.Files))
+	}
+
+	// one transaction emptying the children before their parents, and filling the parents first
+	if strings.Count(dump, "BEGIN;") != 1 || strings.Count(dump, "COMMIT;") != 1 {
+		t.Errorf("the dump is not one transaction:\n%s", dump)
+	}
+	order := []string{
+		`DELETE FROM "shops";`,
+		`DELETE FROM "items_Rewards";`,
+		`DELETE FROM "items";`,
+		`INSERT INTO "items"`,
+		`INSERT INTO "items_Rewards"`,
+		`INSERT INTO "shops"`,
+	}
+	prev := -1
+	for _, statement := range order {
+		i := strings.Index(dump, statement)
+		if i <= prev {
+			t.Fatalf("%s is out of order:\n%s", statement, dump)
+		}
+		prev = i
+	}
+
+	// running the dump twice reloads the tables
+	db, err := sql.Open("sqlite", filepath.Join(rootDir, "tables.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("PRAGMA foreign_keys = ON;"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(string(result.Files[filepath.Join(rootDir, "schema.sql")])); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if _, err := db.Exec(dump); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var items, rewards int
+	if err := db.QueryRow(`SELECT (SELECT COUNT(*) FROM "items"), (SELECT COUNT(*) FROM "items_Rewards")`).Scan(&items, &rewards); err != nil {
+		t.Fatal(err)
+	}
+	if items != 2 || rewards != 3 {
+		t.Errorf("items = %d, rewards = %d, want 2, 3", items, rewards)
+	}
+}
@@ -0,0 +1,187 @@
+package nestcsv
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// TableWriterSQLite - writes every table into a single sqlite database, with the tables of the sql_ddl codegen
+type TableWriterSQLite struct {
+	RootDir  string `yaml:"root_dir"`
+	FileName string `yaml:"file_name"` // default "tables.db"
+
+	mu     sync.Mutex
+	tables map[string]*sqlTableRows
+}
+
+func (e *TableWriterSQLite) Write(ctx context.Context, files *FileSink, name string, value any) error {
+	return errors.New("sqlite output requires the table schema")
+}
+
+// WriteSchema - collects the rows of a table, they are written by Flush
+func (e *TableWriterSQLite) WriteSchema(ctx context.Context, files *FileSink, schema *TableSchema, value any) error {
+	table, err := newSQLTableRows(schema, value)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.tables == nil {
+		e.tables = make(map[string]*sqlTableRows)
+	}
+	e.tables[schema.Table.Name] = table
+	return nil
+}
+
+// Flush - recreates the database with the collected tables, it's written to a temporary file and renamed,
+// so a browser having the database open never sees a half written one
+func (e *TableWriterSQLite) Flush(ctx context.Context, files *FileSink) error {
+	e.mu.Lock()
+	collected := e.tables
+	e.tables = nil
+	e.mu.Unlock()
+	if len(collected) == 0 {
+		return nil
+	}
+
+	if e.RootDir == "" {
+		e.RootDir = "."
+	}
+	if e.FileName == "" {
+		e.FileName = "tables.db"
+	}
+	filePath := makeFilePath(e.RootDir, e.FileName, cmp.Or(filepath.Ext(e.FileName), ".db"))
+	files.add(e.RootDir, filePath)
+	if files != nil && files.capture {
+		return e.captureDatabase(files, filePath, collected)
+	}
+
+	if err := os.MkdirAll(e.RootDir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create the directory: %s, %w", e.RootDir, err)
+	}
+	tmpPath := filePath + ".tmp"
+	if err := os.Remove(tmpPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if err := e.writeDatabase(tmpPath, collected); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write the database: %s, %w", filePath, err)
+	}
+	return os.Rename(tmpPath, filePath)
+}
+
+// captureDatabase - a capturing sink gets the database written out of the root directory
+func (e *TableWriterSQLite) captureDatabase(files *FileSink, filePath string, collected map[string]*sqlTableRows) error {
+	tmp, err := os.CreateTemp("", "nestcsv-*.db")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := e.writeDatabase(tmp.Name(), collected); err != nil {
+		return fmt.Errorf("failed to write the database: %s, %w", filePath, err)
+	}
+	data, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return err
+	}
+	return files.WriteFile(filePath, data)
+}
+
+func (e *TableWriterSQLite) writeDatabase(path string, collected map[string]*sqlTableRows) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	names := make([]string, 0, len(collected))
+	for name := range collected {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		table := collected[name]
+		var ddl strings.Builder
+		if err := writeSQLDDL(&ddl, sqlDialectSQLite, table.tables, false); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ddl.String()); err != nil {
+			return fmt.Errorf("failed to create the table: %s, %w", name, err)
+		}
+		for _, t := range table.tables {
+			if err := e.insert(tx, t, table.rows[t]); err != nil {
+				return fmt.Errorf("failed to insert the rows: %s, %w", t.Name, err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (e *TableWriterSQLite) insert(tx *sql.Tx, table *sqlTable, rows [][]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	params := strings.Repeat(", ?", len(table.Columns))[2:]
+	dialect := sqlDialect(sqlDialectSQLite)
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", dialect.quote(table.Name), dialect.quoteList(table.Columns), params))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	args := make([]any, len(table.Columns))
+	for _, row := range rows {
+		for i, column := range table.Columns {
+			arg, err := sqliteValue(column, row[i])
+			if err != nil {
+				return fmt.Errorf("%s: %w", column.Name, err)
+			}
+			args[i] = arg
+		}
+		if _, err := stmt.Exec(args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sqliteValue - a json or a cell array is stored as its json text, and a time as the text sqlite date functions read
+func sqliteValue(column *sqlColumn, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if column.IsJSON {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}
+	if t, ok := value.(time.Time); ok {
+		return t.UTC().Format(sqlTimeLayout), nil
+	}
+	return value, nil
+}
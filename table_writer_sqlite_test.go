@@ -0,0 +1,103 @@
+package nestcsv
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestTableWriterSQLite(t *testing.T) {
+	newConfig := func(rootDir string) *Config {
+		return &Config{
+			Datasources: []DatasourceConfig{NewDatasourceConfig(&DatasourceMemory{Tables: []MemoryTable{
+				{File: "items.csv", Rows: [][]string{
+					{""}, {"all", "all", "all", "all"}, {"ID", "Name", "[]Rewards.Type", "[]Rewards.Count"}, {"int", "string?", "string", "int"}, {""},
+					{"1", "sword", "gold", "10"}, {"1", "", "gem", "1"}, {"2", "", "gold", "5"},
+				}},
+				{File: "shops.csv", Rows: [][]string{{""}, {"all", "all"}, {"ID", "Name"}, {"int", "string"}, {""}, {"1", "main"}}},
+			}})},
+			Outputs: []OutputConfig{NewOutputConfig(&TableWriterSQLite{RootDir: rootDir}, "all")},
+		}
+	}
+	query := func(t *testing.T, path string) {
+		t.Helper()
+		db, err := sql.Open("sqlite", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		var got []string
+		rows, err := db.Query(`SELECT i."ID", COALESCE(i."Name", 'NULL'), r."_ordinal", r."Type", r."Count"
+			FROM "items" i JOIN "items_Rewards" r ON r."items_ID" = i."ID" ORDER BY i."ID", r."_ordinal"`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var (
+				id, ordinal, count int
+				name, typ          string
+			)
+			if err := rows.Scan(&id, &name, &ordinal, &typ, &count); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, fmt.Sprintf("%d %s %d %s %d", id, name, ordinal, typ, count))
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"1 sword 0 gold 10", "1 sword 1 gem 1", "2 NULL 0 gold 5"}
+		if !slices.Equal(got, want) {
+			t.Errorf("rows = %q, want %q", got, want)
+		}
+
+		var shop string
+		if err := db.QueryRow(`SELECT "Name" FROM "shops" WHERE "ID" = 1`).Scan(&shop); err != nil {
+			t.Fatal(err)
+		}
+		if shop != "main" {
+			t.Errorf("shop = %s, want main", shop)
+		}
+	}
+
+	t.Run("disk", func(t *testing.T) {
+		rootDir := t.TempDir()
+		if err := Generate(newConfig(rootDir)); err != nil {
+			t.Fatal(err)
+		}
+		// a second run replaces the database
+		if err := Generate(newConfig(rootDir)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(rootDir, "tables.db.tmp")); !os.IsNotExist(err) {
+			t.Errorf("the temporary database is left: %v", err)
+		}
+		query(t, filepath.Join(rootDir, "tables.db"))
+	})
+
+	t.Run("capture", func(t *testing.T) {
+		rootDir := t.TempDir()
+		result, err := Run(context.Background(), newConfig(rootDir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, ok := result.Files[filepath.Join(rootDir, "tables.db")]
+		if !ok {
+			t.Fatalf("the database is not captured: %v", slices.Collect(maps.Keys(result.Files)))
+		}
+		if entries, _ := os.ReadDir(rootDir); len(entries) != 0 {
+			t.Errorf("the capture wrote into the root directory: %v", entries)
+		}
+		path := filepath.Join(t.TempDir(), "tables.db")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		query(t, path)
+	})
+}
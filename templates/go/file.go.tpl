@@ -23,9 +23,57 @@ type {{ pascal .Name }} struct {
     {{ pascal .Name }} {{ fieldType . }} `json:"{{ .Name }}"`
 {{- end }}
 }
+{{- $struct := . }}
+{{- range .Fields }}
+{{- if .TableRef }}
+{{- $refType := pascal .TableRef.Struct.Name }}
+{{- if .IsArray }}
+
+func (r *{{ pascal $struct.Name }}) {{ pascal .Name }}Ref(tables *TableHolder) []*{{ $refType }} {
+    rows := make([]*{{ $refType }}, 0, len(r.{{ pascal .Name }}))
+    for _, id := range r.{{ pascal .Name }} {
+        if row, ok := tables.{{ $refType }}.Find(id); ok {
+            rows = append(rows, row)
+        }
+    }
+    return rows
+}
+{{- else }}
+
+func (r *{{ pascal $struct.Name }}) {{ pascal .Name }}Ref(tables *TableHolder) (*{{ $refType }}, bool) {
+    {{- if .IsNullable }}
+    if r.{{ pascal .Name }} == nil {
+        return nil, false
+    }
+    return tables.{{ $refType }}.Find(*r.{{ pascal .Name }})
+    {{- else }}
+    return tables.{{ $refType }}.Find(r.{{ pascal .Name }})
+    {{- end }}
+}
+{{- end }}
+{{- end }}
+{{- end }}
+
+func (r *{{ pascal $struct.Name }}) readBin(br *binReader) {
+{{- range .Fields }}
+    {{- if .IsArray }}
+    r.{{ pascal .Name }} = readBinList(br, func() {{ fieldElemType . }} { return {{ template "binValue" . }} })
+    {{- else if and .IsNullable (eq .Type "json") }}
+    if br.bool() {
+        r.{{ pascal .Name }} = br.json()
+    }
+    {{- else if .IsNullable }}
+    r.{{ pascal .Name }} = readBinNullable(br, func() {{ fieldElemType . }} { return {{ template "binValue" . }} })
+    {{- else }}
+    r.{{ pascal .Name }} = {{ template "binValue" . }}
+    {{- end }}
+{{- end }}
+}
 {{ end }}
 
 {{ if .IsTable }}
+const {{ pascal .Struct.Name }}BinSchema uint32 = {{ printf "0x%08x" .BinSchema }}
+
 type {{ pascal .Struct.Name }}Table struct{
     {{- if .IsMap }}
     Rows map[string]{{ pascal .Struct.Name }}
@@ -82,5 +130,51 @@ func (t *{{ pascal .Struct.Name }}Table) LoadFromFile(basePath string) error {
 
     return json.NewDecoder(file).Decode(&t.Rows)
 }
+
+func (t *{{ pascal .Struct.Name }}Table) LoadBin(data []byte) error {
+    br, err := newBinReader(data, {{ pascal .Struct.Name }}BinSchema)
+    if err != nil {
+        return err
+    }
+    count := br.len()
+    {{- if .IsMap }}
+    rows := make(map[string]{{ pascal .Struct.Name }}, count)
+    for i := 0; i < count; i++ {
+        key := br.string()
+        rows[key] = readBinStruct[{{ pascal .Struct.Name }}](br)
+    }
+    {{- else }}
+    rows := make([]{{ pascal .Struct.Name }}, count)
+    for i := range rows {
+        rows[i].readBin(br)
+    }
+    {{- end }}
+    if err := br.finish(); err != nil {
+        return err
+    }
+    t.Rows = rows
+    return nil
+}
+
+func (t *{{ pascal .Struct.Name }}Table) LoadBinFromFile(basePath string) error {
+    data, err := os.ReadFile(filepath.Join(basePath, "{{ .Name }}.bin"))
+    if err != nil {
+        return err
+    }
+    return t.LoadBin(data)
+}
+{{- end }}
+{{- end -}}
+
+{{- define "binValue" -}}
+{{- if eq .Type "struct" }}readBinStruct[{{ pascal .StructRef.Name }}](br)
+{{- else if eq .Type "enum" }}readBinEnum(br, {{ pascal .EnumRef.Name }}Values)
+{{- else if eq .Type "int" }}{{ fieldPrimitiveType .Type }}(br.varint())
+{{- else if eq .Type "long" }}br.varint()
+{{- else if eq .Type "float" }}br.float()
+{{- else if eq .Type "bool" }}br.bool()
+{{- else if eq .Type "string" }}br.string()
+{{- else if eq .Type "time" }}br.time()
+{{- else if eq .Type "json" }}br.json()
 {{- end }}
 {{- end -}}
@@ -3,6 +3,7 @@
 #pragma once
 
 #include "Json.h"
+#include "{{ .Prefix }}BinReader{{ .FileSuffix }}"
 #include "{{ .Prefix }}TableBase.generated.h"
 
 USTRUCT(BlueprintType)
@@ -25,4 +26,5 @@ struct F{{ .Prefix }}TableBase
         }
         return false;
     }
+    virtual bool LoadBin(const TArray<uint8>& Bytes) { return false; }
 };
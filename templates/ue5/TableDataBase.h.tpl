@@ -3,6 +3,7 @@
 #pragma once
 
 #include "Json.h"
+#include "{{ .Prefix }}BinReader{{ .FileSuffix }}"
 #include "{{ .Prefix }}TableDataBase.generated.h"
 
 USTRUCT(BlueprintType)
@@ -24,4 +25,5 @@ struct F{{ .Prefix }}TableDataBase
         }
         return false;
     }
+    virtual void ReadBin(F{{ .Prefix }}BinReader& Reader) {}
 };
@@ -14,6 +14,13 @@
 //NESTCSV:{{ $extraInclude }}_END
 
 #include "{{ $.Prefix }}{{ pascal .Name }}.generated.h"
+{{- range append .AnonymousStructs .Struct }}
+{{- range .Fields }}
+{{- if .TableRef }}
+struct F{{ $.Prefix }}{{ pascal .TableRef.Struct.Name }};
+{{- end }}
+{{- end }}
+{{- end }}
 {{ range append .AnonymousStructs .Struct }}
 USTRUCT(BlueprintType)
 struct F{{ $.Prefix }}{{ pascal .Name }} : public F{{ $.Prefix }}TableDataBase
@@ -21,7 +28,9 @@ struct F{{ $.Prefix }}{{ pascal .Name }} : public F{{ $.Prefix }}TableDataBase
     GENERATED_BODY()
 
     {{- range .Fields }}
+    {{- if or (not .IsNullable) (eq .Type "json") }}
     UPROPERTY(VisibleAnywhere, BlueprintReadOnly)
+    {{- end }}
     {{ fieldType . }} {{ .Name }};
     {{- end }}
 
@@ -31,7 +40,35 @@ struct F{{ $.Prefix }}{{ pascal .Name }} : public F{{ $.Prefix }}TableDataBase
         F{{ $.Prefix }}{{ pascal .Name }} _Result;
 {{/**/}}
         {{- range .Fields }}
-        {{- if .IsArray }}
+        {{- if .IsNullable }}
+        {
+            const TSharedPtr<FJsonValue> Item = JsonObject->TryGetField(TEXT("{{ .Name }}"));
+            if (Item.IsValid() && !Item->IsNull())
+            {
+                {{- if eq .Type "json" }}
+                _Result.{{ .Name }} = Item;
+                {{- else }}
+                {{ fieldElemType . }} FieldItem;
+                {{- if eq .Type "time" }}
+                FString DateTimeStr;
+                if (!Item->TryGetString(DateTimeStr)) return false;
+                if (!FDateTime::ParseIso8601(*DateTimeStr, FieldItem)) return false;
+                {{- else if eq .Type "enum" }}
+                FString EnumStr;
+                if (!Item->TryGetString(EnumStr)) return false;
+                if (!LexTryParseString(FieldItem, *EnumStr)) return false;
+                {{- else if eq .Type "bool" }}
+                if (!Item->TryGetBool(FieldItem)) return false;
+                {{- else if eq .Type "string" }}
+                if (!Item->TryGetString(FieldItem)) return false;
+                {{- else }}
+                if (!Item->TryGetNumber(FieldItem)) return false;
+                {{- end }}
+                _Result.{{ .Name }} = FieldItem;
+                {{- end }}
+            }
+        }
+        {{- else if .IsArray }}
         {
             const TArray<TSharedPtr<FJsonValue>>* {{ .Name }}Array = nullptr;
             if (!JsonObject.ToSharedRef()->TryGetArrayField(TEXT("{{ .Name }}"), {{ .Name }}Array)) return false;
@@ -45,6 +82,12 @@ struct F{{ $.Prefix }}{{ pascal .Name }} : public F{{ $.Prefix }}TableDataBase
                 _Result.{{ .Name }}.Add(DateTime);
                 {{- else if eq .Type "json" }}
                 _Result.{{ .Name }}.Add(Item);
+                {{- else if eq .Type "enum" }}
+                FString EnumStr;
+                if (!Item->TryGetString(EnumStr)) return false;
+                {{ fieldElemType . }} FieldItem;
+                if (!LexTryParseString(FieldItem, *EnumStr)) return false;
+                _Result.{{ .Name }}.Add(FieldItem);
                 {{- else if eq .Type "struct" }}
                 const TSharedPtr<FJsonObject> *ObjPtr = nullptr;
                 if (!Item->TryGetObject(ObjPtr)) return false;
@@ -84,6 +127,12 @@ struct F{{ $.Prefix }}{{ pascal .Name }} : public F{{ $.Prefix }}TableDataBase
             if (!JsonObject.ToSharedRef()->TryGetStringField(TEXT("{{ .Name }}"), {{ .Name }}DtStr)) return false;
             if (!FDateTime::ParseIso8601(*{{ .Name }}DtStr, _Result.{{ .Name }})) return false;
         }
+        {{- else if eq .Type "enum" }}
+        {
+            FString {{ .Name }}EnumStr;
+            if (!JsonObject.ToSharedRef()->TryGetStringField(TEXT("{{ .Name }}"), {{ .Name }}EnumStr)) return false;
+            if (!LexTryParseString(_Result.{{ .Name }}, *{{ .Name }}EnumStr)) return false;
+        }
         {{- else if eq .Type "json" }}
         if (!JsonObject.ToSharedRef()->TryGetField(TEXT("{{ .Name }}"), _Result.{{ .Name }})) return false;
         {{- else if eq .Type "struct" }}
@@ -99,6 +148,62 @@ struct F{{ $.Prefix }}{{ pascal .Name }} : public F{{ $.Prefix }}TableDataBase
         return true;
     }
 
+    virtual void ReadBin(F{{ $.Prefix }}BinReader& Reader) override
+    {
+        {{- range .Fields }}
+        {{- if .IsArray }}
+        {
+            const int32 Count = Reader.ReadLength();
+            {{ .Name }}.Empty(Count);
+            for (int32 Index = 0; Index < Count && Reader.IsValid(); ++Index)
+            {
+                {{- if eq .Type "struct" }}
+                {{ .Name }}.AddDefaulted_GetRef().ReadBin(Reader);
+                {{- else }}
+                {{ .Name }}.Add({{ template "binValue" . }});
+                {{- end }}
+            }
+        }
+        {{- else if .IsNullable }}
+        if (Reader.ReadBool()) {{ .Name }} = {{ template "binValue" . }};
+        else {{ .Name }}.Reset();
+        {{- else if eq .Type "struct" }}
+        {{ .Name }}.ReadBin(Reader);
+        {{- else }}
+        {{ .Name }} = {{ template "binValue" . }};
+        {{- end }}
+        {{- end }}
+    }
+{{- range .Fields }}
+{{- if .TableRef }}
+{{- $refType := list "F" $.Prefix (pascal .TableRef.Struct.Name) | join "" }}
+{{- if .IsArray }}
+
+    template <class THolder>
+    TArray<const {{ $refType }}*> Resolve{{ .Name }}(const THolder* Tables) const
+    {
+        TArray<const {{ $refType }}*> Rows;
+        for (const auto& ID : {{ .Name }})
+        {
+            if (const {{ $refType }}* Row = Tables->{{ pascal .TableRef.Name }}.Find(ID)) Rows.Add(Row);
+        }
+        return Rows;
+    }
+{{- else }}
+
+    template <class THolder>
+    const {{ $refType }}* Resolve{{ .Name }}(const THolder* Tables) const
+    {
+        {{- if .IsNullable }}
+        return {{ .Name }}.IsSet() ? Tables->{{ pascal .TableRef.Name }}.Find({{ .Name }}.GetValue()) : nullptr;
+        {{- else }}
+        return Tables->{{ pascal .TableRef.Name }}.Find({{ .Name }});
+        {{- end }}
+    }
+{{- end }}
+{{- end }}
+{{- end }}
+
     {{ $extraBody := list $.Prefix .Name "_EXTRA_BODY" | join "" | upper -}}
     //NESTCSV:{{ $extraBody }}_START
     {{ index $.ExistingContent $extraBody | default "" }}
@@ -106,3 +211,14 @@ struct F{{ $.Prefix }}{{ pascal .Name }} : public F{{ $.Prefix }}TableDataBase
 };
 {{ end }}
 {{- end -}}
+{{- define "binValue" -}}
+{{- if eq .Type "enum" }}static_cast<{{ fieldElemType . }}>(Reader.ReadEnum({{ len .EnumRef.Values }}))
+{{- else if eq .Type "int" }}static_cast<int32>(Reader.ReadVarint())
+{{- else if eq .Type "long" }}Reader.ReadVarint()
+{{- else if eq .Type "float" }}Reader.ReadFloat()
+{{- else if eq .Type "bool" }}Reader.ReadBool()
+{{- else if eq .Type "string" }}Reader.ReadString()
+{{- else if eq .Type "time" }}Reader.ReadTime()
+{{- else if eq .Type "json" }}Reader.ReadJson()
+{{- end }}
+{{- end -}}
@@ -64,6 +64,32 @@ struct F{{ $.Prefix }}{{ pascal .Name }}Table : public F{{ $.Prefix }}TableBase
         Rows = MoveTemp(_Result);
         return true;
     }
+
+    virtual bool LoadBin(const TArray<uint8>& Bytes) override
+    {
+        F{{ $.Prefix }}BinReader Reader(Bytes, {{ printf "0x%08X" .BinSchema }}u);
+        const int32 Count = Reader.ReadLength();
+        {{- if .IsMap }}
+        TMap<FString, F{{ $.Prefix }}{{ pascal .Name }}> _Result;
+        _Result.Reserve(Count);
+        for (int32 Index = 0; Index < Count && Reader.IsValid(); ++Index)
+        {
+            const FString Key = Reader.ReadString();
+            _Result.Add(Key).ReadBin(Reader);
+        }
+        {{- else }}
+        TArray<F{{ $.Prefix }}{{ pascal .Name }}> _Result;
+        _Result.Reserve(Count);
+        for (int32 Index = 0; Index < Count && Reader.IsValid(); ++Index)
+        {
+            _Result.AddDefaulted_GetRef().ReadBin(Reader);
+        }
+        {{- end }}
+        if (!Reader.IsFinished()) return false;
+
+        Rows = MoveTemp(_Result);
+        return true;
+    }
 {{- if or .IDField .IsMap }}
 
     const F{{ $.Prefix }}{{ pascal .Name }}* Find({{ fieldPrimitiveType .IDFieldType }} ID) const
@@ -8,6 +8,7 @@ namespace {{ .Namespace }}
 [Serializable]
 public abstract class {{ .Prefix }}TableDataBase
 {
+    public abstract void ReadBin({{ .Prefix }}BinReader reader);
 }
 {{- if .Namespace }}
 }
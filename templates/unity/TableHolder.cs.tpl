@@ -59,6 +59,17 @@ public partial class {{ .Prefix }}TableHolder
         return holder;
     }
 
+    public void LoadBinFromFile(string basePath, string fileSuffix = ".bin")
+    {
+        foreach (var table in GetTables())
+        {
+            table.LoadBinFromFile(basePath, fileSuffix);
+        }
+{{- if .Singleton }}
+        Instance = this;
+{{- end }}
+    }
+
     public bool LoadFromJsonMap(IReadOnlyDictionary<string, string> jsonBySheet)
     {
         var success = true;
@@ -86,6 +97,20 @@ public partial class {{ .Prefix }}TableHolder
         }
         return success;
     }
+
+    public bool LoadFromBinMap(IReadOnlyDictionary<string, byte[]> binBySheet)
+    {
+        var success = true;
+        foreach (var kv in binBySheet)
+        {
+            var table = GetTable(kv.Key);
+            if (table == null || !table.LoadBin(kv.Value))
+            {
+                success = false;
+            }
+        }
+        return success;
+    }
 {{- if .ResourceFolder }}
 
     public void LoadFromResources(string resourceFolder = "{{ .ResourceFolder }}")
@@ -3,6 +3,12 @@
 
 using System;
 using System.Collections.Generic;
+{{- if and .IsMap (in .IDFieldType "int" "long") }}
+using System.Globalization;
+{{- end }}
+{{- if .IsTable }}
+using System.IO;
+{{- end }}
 using Newtonsoft.Json;
 {{- if has .FieldTypes "json" }}
 using Newtonsoft.Json.Linq;
@@ -24,6 +30,52 @@ public partial class {{ $.Prefix }}{{ pascal $s.Name }}{{ $.DataSuffix }} : {{ $
     [JsonProperty("{{ .Name }}")]
     public {{ fieldType . }} {{ pascal .Name }};
 {{- end }}
+{{- range $s.Fields }}
+{{- if .TableRef }}
+{{- $refType := list $.Prefix (pascal .TableRef.Struct.Name) $.DataSuffix | join "" }}
+{{- if .IsArray }}
+
+    public List<{{ $refType }}> Resolve{{ pascal .Name }}({{ $.Prefix }}TableHolder tables)
+    {
+        var rows = new List<{{ $refType }}>({{ pascal .Name }}.Count);
+        foreach (var id in {{ pascal .Name }})
+        {
+            var row = tables.{{ pascal .TableRef.Name }}.Find(id);
+            if (row != null)
+            {
+                rows.Add(row);
+            }
+        }
+        return rows;
+    }
+{{- else }}
+
+    public {{ $refType }} Resolve{{ pascal .Name }}({{ $.Prefix }}TableHolder tables)
+    {
+{{- if and .IsNullable (ne .Type "string") }}
+        return {{ pascal .Name }}.HasValue ? tables.{{ pascal .TableRef.Name }}.Find({{ pascal .Name }}.Value) : null;
+{{- else }}
+        return tables.{{ pascal .TableRef.Name }}.Find({{ pascal .Name }});
+{{- end }}
+    }
+{{- end }}
+{{- end }}
+{{- end }}
+
+    public override void ReadBin({{ $.Prefix }}BinReader reader)
+    {
+{{- range $s.Fields }}
+{{- if .IsArray }}
+        {{ pascal .Name }} = reader.ReadList(() => {{ template "binValue" . }});
+{{- else if and .IsNullable (in .Type "string" "json") }}
+        {{ pascal .Name }} = reader.ReadBool() ? {{ template "binValue" . }} : null;
+{{- else if .IsNullable }}
+        {{ pascal .Name }} = reader.ReadBool() ? {{ template "binValue" . }} : ({{ fieldType . }})null;
+{{- else }}
+        {{ pascal .Name }} = {{ template "binValue" . }};
+{{- end }}
+{{- end }}
+    }
 }
 {{- end }}
 {{- if .IsTable }}
@@ -53,6 +105,42 @@ public partial class {{ $.Prefix }}{{ pascal .Struct.Name }}{{ $.TableSuffix }}
 {{- end }}
         return true;
     }
+
+    public override bool LoadBin(byte[] bytes)
+    {
+        try
+        {
+            var reader = new {{ $.Prefix }}BinReader(bytes, {{ printf "0x%08X" .BinSchema }}u);
+            var count = reader.ReadLength();
+{{- if .IsMap }}
+            var result = new Dictionary<{{ fieldPrimitiveType .IDFieldType }}, {{ $.Prefix }}{{ pascal .Struct.Name }}{{ $.DataSuffix }}>(count);
+            for (var i = 0; i < count; i++)
+            {
+{{- if eq .IDFieldType "int" }}
+                var key = int.Parse(reader.ReadString(), CultureInfo.InvariantCulture);
+{{- else if eq .IDFieldType "long" }}
+                var key = long.Parse(reader.ReadString(), CultureInfo.InvariantCulture);
+{{- else }}
+                var key = reader.ReadString();
+{{- end }}
+                result[key] = reader.ReadStruct<{{ $.Prefix }}{{ pascal .Struct.Name }}{{ $.DataSuffix }}>();
+            }
+{{- else }}
+            var result = new List<{{ $.Prefix }}{{ pascal .Struct.Name }}{{ $.DataSuffix }}>(count);
+            for (var i = 0; i < count; i++)
+            {
+                result.Add(reader.ReadStruct<{{ $.Prefix }}{{ pascal .Struct.Name }}{{ $.DataSuffix }}>());
+            }
+{{- end }}
+            reader.Finish();
+            Rows = result;
+            return true;
+        }
+        catch (InvalidDataException)
+        {
+            return false;
+        }
+    }
 {{- if or .IDField .IsMap }}
 
     public {{ $.Prefix }}{{ pascal .Struct.Name }}{{ $.DataSuffix }} Find({{ fieldPrimitiveType .IDFieldType }} id)
@@ -116,3 +204,16 @@ public partial class {{ $.Prefix }}{{ pascal .Struct.Name }}{{ $.TableSuffix }}
 }
 {{- end }}
 {{- end -}}
+
+{{- define "binValue" -}}
+{{- if eq .Type "struct" }}reader.ReadStruct<{{ fieldElemType . }}>()
+{{- else if eq .Type "enum" }}({{ fieldElemType . }})reader.ReadEnum({{ len .EnumRef.Values }})
+{{- else if eq .Type "int" }}(int)reader.ReadVarint()
+{{- else if eq .Type "long" }}reader.ReadVarint()
+{{- else if eq .Type "float" }}reader.ReadFloat()
+{{- else if eq .Type "bool" }}reader.ReadBool()
+{{- else if eq .Type "string" }}reader.ReadString()
+{{- else if eq .Type "time" }}reader.ReadTime()
+{{- else if eq .Type "json" }}reader.ReadJson()
+{{- end }}
+{{- end -}}
\ No newline at end of file
@@ -2,7 +2,7 @@ package nestcsv
 
 import (
 	"embed"
-	"encoding/csv"
+	"errors"
 	"fmt"
 	"github.com/Masterminds/sprig/v3"
 	"github.com/gertd/go-pluralize"
@@ -62,9 +62,12 @@ func glob(patterns []string) iter.Seq[string] {
 				log.Panicf("failed to glob: %s, %v", pattern, err)
 			}
 			for _, match := range matches {
-				match, err = filepath.Rel(".", match)
-				if err != nil {
-					log.Panicf("failed to get relative path: %s, %v", match, err)
+				// an absolute pattern keeps its matches absolute
+				if !filepath.IsAbs(match) {
+					match, err = filepath.Rel(".", match)
+					if err != nil {
+						log.Panicf("failed to get relative path: %s, %v", match, err)
+					}
 				}
 				if _, ok := visited[match]; ok {
 					continue
@@ -80,44 +83,58 @@ func glob(patterns []string) iter.Seq[string] {
 }
 
 func makeFilePath(rootDir, fileName, ext string) string {
-	ext = "." + strings.TrimPrefix(ext, ".")
-	fileName = strings.TrimSuffix(fileName, ext) + ext
-	return filepath.Join(rootDir, fileName)
-}
-
-func createFile(rootDir, fileName, ext string) (*os.File, error) {
-	if err := os.MkdirAll(rootDir, os.ModePerm); err != nil {
-		return nil, fmt.Errorf("failed to create the directory: %s, %w", rootDir, err)
-	}
-
-	filePath := makeFilePath(rootDir, fileName, ext)
-	file, err := os.Create(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create the file: %s, %w", filePath, err)
+	if ext = strings.TrimPrefix(ext, "."); ext != "" {
+		fileName = strings.TrimSuffix(fileName, "."+ext) + "." + ext
 	}
-	return file, nil
+	return filepath.Join(rootDir, fileName)
 }
 
-func saveCSVFile(rootDir, fileName string, csvData [][]string) error {
+// padRows - pads the rows to the same length, and appends empty rows up to minRows
+func padRows(csvData [][]string, minRows int) [][]string {
 	maxLen := 0
 	for _, row := range csvData {
 		if len(row) > maxLen {
 			maxLen = len(row)
 		}
 	}
+	for len(csvData) < minRows {
+		csvData = append(csvData, nil)
+	}
 	for i, row := range csvData {
 		if len(row) < maxLen {
 			csvData[i] = append(row, make([]string, maxLen-len(row))...)
 		}
 	}
+	return csvData
+}
 
-	file, err := createFile(rootDir, fileName, "csv")
+// padTableRows - pads the rows of a sheet to the same length and up to the data start row of its metadata,
+// since some datasources trim the trailing empty cells and rows
+func padTableRows(csvData [][]string) [][]string {
+	dataStartRow := TableDataStartRow
+	if len(csvData) > TableMetadataRow {
+		if metadata, err := TableMetadataQuery(cellAt(csvData[TableMetadataRow], 0)).Decode(); err == nil {
+			dataStartRow = metadata.dataStartRow()
+		}
+	}
+	return padRows(csvData, dataStartRow)
+}
+
+// saveCSVFile - saves the rows in the dialect, a nil dialect is the standard csv
+func saveCSVFile(files *FileSink, rootDir, fileName string, csvData [][]string, dialect *CSVDialect) error {
+	csvData = padRows(csvData, 0)
+
+	ext := "csv"
+	if dialect != nil && dialect.Delimiter == "\t" {
+		ext = "tsv"
+	}
+	file, err := files.Create(rootDir, fileName, ext)
 	if err != nil {
 		return fmt.Errorf("failed to create the file: %s, %w", fileName, err)
 	}
 	defer file.Close()
 
-	if err := csv.NewWriter(file).WriteAll(csvData); err != nil {
+	if err := dialect.WriteAll(file, ',', csvData); err != nil {
 		return fmt.Errorf("failed to write the file: %s, %w", fileName, err)
 	}
 	return nil
@@ -223,3 +240,16 @@ func initTemplateFuncs() template.FuncMap {
 	m["in"] = in
 	return m
 }
+
+// parseTemplate - parses the template of the template directory if it has one of the name, otherwise the embedded one
+func parseTemplate(tmpl *template.Template, templateDir, embedDir, templateName string) (*template.Template, error) {
+	if templateDir != "" {
+		path := filepath.Join(templateDir, templateName)
+		if _, err := os.Stat(path); err == nil {
+			return tmpl.ParseFiles(path)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return tmpl.ParseFS(templateFS, "templates/"+embedDir+"/"+templateName)
+}
@@ -0,0 +1,324 @@
+package nestcsv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher - regenerates whenever a file of the datasources or the config file, its includes or env files, changes
+//
+//	Only the tables of the changed files are rewritten, unless an enum is declared by one of them,
+//	and the code is generated again from every table. The datasources not reading the local files,
+//	such as the spreadsheets, are collected once with the config.
+type Watcher struct {
+	ConfigPath string
+	Args       []string
+	// AllErrors - overrides Config.AllErrors, as the -all-errors flag does
+	AllErrors bool
+	// NoPrune - overrides Config.NoPrune, as the -no-prune flag does
+	NoPrune bool
+	// Debounce - waits for the changes to settle, since Excel saves a workbook through temporary files (default 300ms)
+	Debounce time.Duration
+	// OnGenerate - called after every generation with the changed files, empty for the first one, and its error
+	OnGenerate func(changed []string, err error)
+
+	config *Config
+	files  map[string][]*TableData // the tables of each local file
+	others []*TableData            // the tables of the other datasources
+}
+
+// Run - watches until the context is done, a failure of the watcher is logged and doesn't stop it
+func (w *Watcher) Run(ctx context.Context) error {
+	if w.Debounce == 0 {
+		w.Debounce = 300 * time.Millisecond
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create the watcher: %w", err)
+	}
+	defer fsw.Close()
+
+	if err := w.loadConfig(); err != nil {
+		return err
+	}
+	collectErr := w.collectAll(ctx)
+	if err := w.watch(fsw); err != nil {
+		return err
+	}
+	w.generate(ctx, nil, collectErr, nil)
+
+	var (
+		changed = make(map[string]struct{})
+		timer   = time.NewTimer(time.Hour)
+	)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			if !w.isConfigFile(event.Name) && len(w.datasources(event.Name)) == 0 {
+				continue
+			}
+			changed[filepath.Clean(event.Name)] = struct{}{}
+			timer.Reset(w.Debounce)
+
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			// e.g. the event queue overflowed, the next changes are still watched
+			log.Printf("failed to watch the files: %v", err)
+
+		case <-timer.C:
+			paths := slices.Sorted(maps.Keys(changed))
+			clear(changed)
+			w.update(ctx, paths)
+			if err := w.watch(fsw); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// loadConfig - parses the config, the previous one is kept if it fails
+func (w *Watcher) loadConfig() error {
+	config, err := ParseConfig(w.ConfigPath, w.Args)
+	if err != nil {
+		return fmt.Errorf("parse config: %w", err)
+	}
+	if w.AllErrors {
+		config.AllErrors = true
+	}
+	if w.NoPrune {
+		config.NoPrune = true
+	}
+	w.config = config
+	return nil
+}
+
+// collectAll - collects every table of the datasources
+func (w *Watcher) collectAll(ctx context.Context) error {
+	var (
+		errs   []error
+		others []DatasourceConfig
+	)
+	for _, datasource := range w.config.Datasources {
+		if _, ok := datasource.loaded.(FileDatasource); !ok {
+			others = append(others, datasource)
+		}
+	}
+	tables, err := collectTables(ctx, NewFileSink(false), others, w.config.AllErrors)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	w.others = tables
+
+	w.files = make(map[string][]*TableData)
+	var paths []string
+	for _, datasource := range w.config.Datasources {
+		if d, ok := datasource.loaded.(FileDatasource); ok {
+			for path := range glob(d.FilePatterns()) {
+				if !skipFile(path) && !slices.Contains(paths, path) {
+					paths = append(paths, path)
+				}
+			}
+		}
+	}
+	if err := w.collect(ctx, paths); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// collect - collects the files again, a removed file drops its tables
+func (w *Watcher) collect(ctx context.Context, paths []string) error {
+	var errs []error
+	for _, path := range paths {
+		delete(w.files, path)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		var tables []*TableData
+		for _, d := range w.datasources(path) {
+			var (
+				out  = make(chan *TableData)
+				done = make(chan struct{})
+			)
+			go func() {
+				defer close(done)
+				for tableData := range out {
+					tables = append(tables, tableData)
+				}
+			}()
+			err := d.CollectFile(ctx, NewFileSink(false), path, out)
+			close(out)
+			<-done
+			if err != nil {
+				errs = append(errs, fmt.Errorf("collect datasource: %w", err))
+			}
+		}
+		w.files[path] = tables
+	}
+	return errors.Join(errs...)
+}
+
+// update - collects the changed files, or everything if the config file is changed, and generates
+func (w *Watcher) update(ctx context.Context, paths []string) {
+	if slices.ContainsFunc(paths, w.isConfigFile) {
+		if err := w.loadConfig(); err != nil {
+			w.generate(ctx, paths, err, nil)
+			return
+		}
+		w.generate(ctx, paths, w.collectAll(ctx), nil)
+		return
+	}
+
+	rewrite := make(map[string]bool)
+	everything := false
+	mark := func() {
+		for _, path := range paths {
+			for _, tableData := range w.files[path] {
+				rewrite[tableData.Name] = true
+				// the enum values are checked by every table using them
+				everything = everything || tableData.Metadata.AsEnum || len(tableData.Metadata.Enums) > 0
+			}
+		}
+	}
+	mark()
+	collectErr := w.collect(ctx, paths)
+	mark()
+
+	// a removed table rewrites everything too, so that its files are pruned
+	names := make(map[string]bool)
+	for _, tableData := range w.tables() {
+		names[tableData.Name] = true
+	}
+	for name := range rewrite {
+		everything = everything || !names[name]
+	}
+
+	if everything {
+		w.generate(ctx, paths, collectErr, nil)
+		return
+	}
+	w.generate(ctx, paths, collectErr, func(tableData *TableData) bool {
+		return rewrite[tableData.Name]
+	})
+}
+
+// generate - a collect error stops the generation, unless Config.AllErrors is set
+func (w *Watcher) generate(ctx context.Context, changed []string, collectErr error, rewrite func(*TableData) bool) {
+	err := collectErr
+	if err == nil || w.config.AllErrors {
+		files := NewFileSink(false)
+		err = generateTables(ctx, files, w.config, w.tables(), collectErr, rewrite, nil)
+		if err == nil {
+			_, err = files.writeManifests(w.config, w.config.NoPrune)
+		}
+	}
+	if w.OnGenerate != nil {
+		w.OnGenerate(changed, err)
+	}
+}
+
+func (w *Watcher) tables() []*TableData {
+	tables := slices.Clone(w.others)
+	for _, path := range slices.Sorted(maps.Keys(w.files)) {
+		tables = append(tables, w.files[path]...)
+	}
+	return tables
+}
+
+// datasources - the datasources collecting the file
+func (w *Watcher) datasources(path string) []FileDatasource {
+	if w.config == nil {
+		return nil
+	}
+	var datasources []FileDatasource
+	for _, datasource := range w.config.Datasources {
+		if d, ok := datasource.loaded.(FileDatasource); ok && matchFile(d.FilePatterns(), path) {
+			datasources = append(datasources, d)
+		}
+	}
+	return datasources
+}
+
+// configFiles - the config file, its includes and its env files, and the .env file which may be created later
+func (w *Watcher) configFiles() []string {
+	files := []string{w.ConfigPath, filepath.Join(filepath.Dir(w.ConfigPath), ".env")}
+	if w.config != nil {
+		files = append(files, w.config.files...)
+	}
+	return files
+}
+
+func (w *Watcher) isConfigFile(path string) bool {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(w.configFiles(), func(configPath string) bool {
+		configPath, err := filepath.Abs(configPath)
+		return err == nil && path == configPath
+	})
+}
+
+// watch - watches the directories, not the files, since an editor may replace a file by renaming another
+//
+//	The directory of a pattern is watched if it has no wildcard, otherwise the directories of the matched files.
+func (w *Watcher) watch(fsw *fsnotify.Watcher) error {
+	var dirs []string
+	for _, path := range w.configFiles() {
+		dirs = append(dirs, filepath.Dir(path))
+	}
+	for _, datasource := range w.config.Datasources {
+		d, ok := datasource.loaded.(FileDatasource)
+		if !ok {
+			continue
+		}
+		for _, pattern := range d.FilePatterns() {
+			if dir := filepath.Dir(pattern); !strings.ContainsAny(dir, "*?[") {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	for path := range w.files {
+		dirs = append(dirs, filepath.Dir(path))
+	}
+
+	watched := fsw.WatchList()
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		if slices.Contains(watched, dir) {
+			continue
+		}
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		if err := fsw.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch the directory: %s, %w", dir, err)
+		}
+		watched = append(watched, dir)
+	}
+	return nil
+}
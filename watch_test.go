@@ -0,0 +1,203 @@
+package nestcsv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcherUpdate(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	writeTable := func(name, value string) string {
+		return write(name, ",\nall,all\nID,Value\nint,string\n,\n1,"+value+"\n")
+	}
+	writeConfig := func(outDir string) string {
+		return write("nestcsv.yaml", `
+datasources:
+  - csv:
+      patterns: [`+filepath.Join(dir, "*.csv")+`]
+outputs:
+  - tags: [all]
+    json:
+      root_dir: `+filepath.Join(dir, outDir)+`
+`)
+	}
+	itemsPath := writeTable("items.csv", "sword")
+	shopsPath := writeTable("shops.csv", "main")
+	writeGrades := func(grades ...string) string {
+		content := "as_enum=true,\nall,all\nID,Value\nstring,int\n,\n"
+		for i, grade := range grades {
+			content += fmt.Sprintf("%s,%d\n", grade, i)
+		}
+		return write("grades.csv", content)
+	}
+	gradesPath := writeGrades("Common")
+	configPath := writeConfig("json")
+
+	var errs []error
+	w := &Watcher{
+		ConfigPath: configPath,
+		OnGenerate: func(changed []string, err error) {
+			errs = append(errs, err)
+		},
+	}
+	update := func(paths ...string) {
+		t.Helper()
+		// the modification times must differ between the generations
+		time.Sleep(10 * time.Millisecond)
+		w.update(context.Background(), paths)
+		if err := errs[len(errs)-1]; err != nil {
+			t.Fatal(err)
+		}
+	}
+	modTime := func(name string) time.Time {
+		t.Helper()
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.ModTime()
+	}
+
+	if err := w.loadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	w.generate(context.Background(), nil, w.collectAll(context.Background()), nil)
+	if err := errs[0]; err != nil {
+		t.Fatal(err)
+	}
+	itemsTime, shopsTime := modTime("json/items.json"), modTime("json/shops.json")
+
+	// only the table of the changed file is rewritten
+	writeTable("items.csv", "shield")
+	update(itemsPath)
+	if modTime("json/items.json").Equal(itemsTime) {
+		t.Error("items.json is not rewritten")
+	}
+	if !modTime("json/shops.json").Equal(shopsTime) {
+		t.Error("shops.json is rewritten")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "json", "items.json")); string(data) != `[{"ID":1,"Value":"shield"}]` {
+		t.Errorf("items.json = %s", data)
+	}
+
+	// an enum table rewrites every table
+	writeGrades("Common", "Rare")
+	update(gradesPath)
+	if modTime("json/shops.json").Equal(shopsTime) {
+		t.Error("shops.json is not rewritten by the enum change")
+	}
+
+	// a removed table rewrites every table, and its file is pruned
+	itemsTime = modTime("json/items.json")
+	if err := os.Remove(shopsPath); err != nil {
+		t.Fatal(err)
+	}
+	update(shopsPath)
+	if _, err := os.Stat(filepath.Join(dir, "json", "shops.json")); !os.IsNotExist(err) {
+		t.Error("shops.json of the removed table is not pruned")
+	}
+	if modTime("json/items.json").Equal(itemsTime) {
+		t.Error("items.json is not rewritten by the removed table")
+	}
+
+	// the config is reloaded
+	writeConfig("json2")
+	update(configPath)
+	if _, err := os.Stat(filepath.Join(dir, "json2", "items.json")); err != nil {
+		t.Errorf("the reloaded config is not generated: %v", err)
+	}
+}
+
+// manySheetsDatasource - a workbook of more sheets than the buffer of a collect channel
+type manySheetsDatasource struct {
+	path   string
+	sheets int
+}
+
+func (d *manySheetsDatasource) Collect(ctx context.Context, files *FileSink, out chan<- *TableData) error {
+	return d.CollectFile(ctx, files, d.path, out)
+}
+
+func (d *manySheetsDatasource) FilePatterns() []string {
+	return []string{d.path}
+}
+
+func (d *manySheetsDatasource) CollectFile(ctx context.Context, files *FileSink, path string, out chan<- *TableData) error {
+	for i := 0; i < d.sheets; i++ {
+		select {
+		case out <- &TableData{Name: fmt.Sprintf("sheet%d", i)}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func TestWatcherCollectManySheets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w := &Watcher{
+		config: &Config{Datasources: []DatasourceConfig{NewDatasourceConfig(&manySheetsDatasource{path: path, sheets: 1500})}},
+		files:  make(map[string][]*TableData),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := w.collect(ctx, []string{path}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(w.files[path]); n != 1500 {
+		t.Errorf("collected %d sheets, want 1500", n)
+	}
+}
+
+func TestWatcherRunCancel(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "nestcsv.yaml")
+	if err := os.WriteFile(configPath, []byte(`
+datasources:
+  - csv:
+      patterns: [`+filepath.Join(dir, "*.csv")+`]
+outputs:
+  - tags: [all]
+    json:
+      root_dir: `+filepath.Join(dir, "json")+`
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Watcher{
+		ConfigPath: configPath,
+		OnGenerate: func(changed []string, err error) {
+			cancel()
+		},
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx)
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Run() = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't stop with the context")
+	}
+}